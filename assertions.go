@@ -0,0 +1,407 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// Assertion types
+const (
+	AssertStatus       = "status"
+	AssertHeader       = "header"
+	AssertJSONEquals   = "json_equals"
+	AssertJSONContains = "json_contains"
+	AssertRegex        = "regex"
+	AssertMaxLatency   = "max_latency"
+)
+
+// assertionTypes lists the assertion types in the order offered by the editor
+var assertionTypes = []string{AssertStatus, AssertHeader, AssertJSONEquals, AssertJSONContains, AssertRegex, AssertMaxLatency}
+
+// assertionLabels are the assertion type names shown in the editor
+var assertionLabels = map[string]string{
+	AssertStatus:       "Status",
+	AssertHeader:       "Header Equals",
+	AssertJSONEquals:   "JSON Equals",
+	AssertJSONContains: "JSON Contains",
+	AssertRegex:        "Payload Matches",
+	AssertMaxLatency:   "Max Latency",
+}
+
+// Assertion is a check on the response of a saved request
+type Assertion struct {
+	Type string `json:"type"`
+	// Field is the JSON path or header key the assertion applies to
+	Field string `json:"field,omitempty"`
+	Value string `json:"value"`
+}
+
+// String describes the assertion for the response output
+func (a Assertion) String() string {
+	switch a.Type {
+	case AssertStatus:
+		return fmt.Sprintf("status == %s", a.Value)
+	case AssertHeader:
+		return fmt.Sprintf("header %s == %s", a.Field, a.Value)
+	case AssertJSONEquals:
+		return fmt.Sprintf("%s == %s", a.Field, a.Value)
+	case AssertJSONContains:
+		return fmt.Sprintf("%s contains %s", a.Field, a.Value)
+	case AssertRegex:
+		return fmt.Sprintf("payload matches /%s/", a.Value)
+	case AssertMaxLatency:
+		return fmt.Sprintf("latency <= %s", a.Value)
+	}
+	return fmt.Sprintf("%s %s %s", a.Type, a.Field, a.Value)
+}
+
+// AssertionResult is the outcome of an assertion
+type AssertionResult struct {
+	Assertion Assertion
+	Passed    bool
+	Detail    string
+}
+
+// responseStatus returns the status of a request: the Status header, the service
+// error code of NATS micro services, 503 without responders and 200 otherwise
+func responseStatus(msg *nats.Msg, err error) (string, bool) {
+	if errors.Is(err, nats.ErrNoResponders) {
+		return "503", true
+	}
+	if msg == nil {
+		return "", false
+	}
+	if status := msg.Header.Get("Status"); status != "" {
+		return status, true
+	}
+	if code := msg.Header.Get("Nats-Service-Error-Code"); code != "" {
+		return code, true
+	}
+	return "200", true
+}
+
+// evaluateAssertion checks a single assertion against a response
+func evaluateAssertion(a Assertion, msg *nats.Msg, latency time.Duration, reqErr error) AssertionResult {
+	result := AssertionResult{Assertion: a}
+	fail := func(format string, args ...interface{}) AssertionResult {
+		result.Detail = fmt.Sprintf(format, args...)
+		return result
+	}
+	pass := func(format string, args ...interface{}) AssertionResult {
+		result.Passed = true
+		result.Detail = fmt.Sprintf(format, args...)
+		return result
+	}
+
+	switch a.Type {
+	case AssertStatus:
+		status, ok := responseStatus(msg, reqErr)
+		if !ok {
+			return fail("no response: %v", reqErr)
+		}
+		if status != strings.TrimSpace(a.Value) {
+			return fail("got %s", status)
+		}
+		return pass("got %s", status)
+
+	case AssertMaxLatency:
+		limit, err := time.ParseDuration(strings.TrimSpace(a.Value))
+		if err != nil {
+			return fail("invalid duration %q", a.Value)
+		}
+		if msg == nil {
+			return fail("no response: %v", reqErr)
+		}
+		if latency > limit {
+			return fail("took %s", latency.Round(time.Microsecond))
+		}
+		return pass("took %s", latency.Round(time.Microsecond))
+	}
+
+	if msg == nil {
+		return fail("no response: %v", reqErr)
+	}
+
+	switch a.Type {
+	case AssertHeader:
+		// nats.go header keys are case-sensitive, other spellings of the name are
+		// only checked when the exact key is absent
+		values, ok := msg.Header[a.Field]
+		if !ok {
+			for key, v := range msg.Header {
+				if strings.EqualFold(key, a.Field) {
+					values = append(values, v...)
+				}
+			}
+		}
+		if len(values) == 0 {
+			return fail("header missing")
+		}
+		for _, value := range values {
+			if value == a.Value {
+				return pass("got %s", value)
+			}
+		}
+		return fail("got %s", strings.Join(values, ", "))
+
+	case AssertJSONEquals, AssertJSONContains:
+		segments, err := parseJSONPath(a.Field)
+		if err != nil {
+			return fail("%v", err)
+		}
+		var doc interface{}
+		if err := json.Unmarshal(msg.Data, &doc); err != nil {
+			return fail("response is not JSON: %v", err)
+		}
+		v, ok := lookupJSONPath(doc, segments)
+		if !ok {
+			return fail("field missing")
+		}
+		if a.Type == AssertJSONEquals {
+			if jsonValueEquals(v, a.Value) {
+				return pass("got %s", jsonString(v))
+			}
+			return fail("got %s", jsonString(v))
+		}
+		if jsonValueContains(v, a.Value) {
+			return pass("got %s", jsonString(v))
+		}
+		return fail("got %s", jsonString(v))
+
+	case AssertRegex:
+		re, ok, err := parseRegexLiteral(a.Value)
+		if !ok {
+			re, err = regexp.Compile(a.Value)
+		}
+		if err != nil {
+			return fail("invalid regex: %v", err)
+		}
+		if !re.Match(msg.Data) {
+			return fail("no match")
+		}
+		return pass("matched")
+	}
+
+	return fail("unknown assertion type %q", a.Type)
+}
+
+// jsonValueEquals compares a decoded JSON value with an expected value, numerically for numbers
+func jsonValueEquals(v interface{}, expected string) bool {
+	if f, ok := v.(float64); ok {
+		if number, err := strconv.ParseFloat(expected, 64); err == nil {
+			return f == number
+		}
+	}
+	return jsonString(v) == expected
+}
+
+// jsonValueContains reports whether an array holds an element or a value contains a substring
+func jsonValueContains(v interface{}, expected string) bool {
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if jsonValueEquals(item, expected) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(jsonString(v), expected)
+}
+
+// evaluateAssertions checks all assertions against a response
+func evaluateAssertions(assertions []Assertion, msg *nats.Msg, latency time.Duration, reqErr error) []AssertionResult {
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		results = append(results, evaluateAssertion(a, msg, latency, reqErr))
+	}
+	return results
+}
+
+// checkAssertionMode rejects assertions on requests of a mode without responses
+func checkAssertionMode(mode string, assertions []Assertion) error {
+	if mode == "Publish" && len(assertions) > 0 {
+		return fmt.Errorf("assertions need a response, use Request-Reply or Request-Many mode")
+	}
+	return nil
+}
+
+// requestPassed reports whether a request passed: all assertions hold, or without
+// assertions the request got a response
+func requestPassed(results []AssertionResult, reqErr error) bool {
+	if len(results) == 0 {
+		return reqErr == nil
+	}
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// formatAssertionResults formats assertion results for the response output
+func formatAssertionResults(results []AssertionResult) string {
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+
+	verdict := "PASSED"
+	if passed < len(results) {
+		verdict = "FAILED"
+	}
+
+	lines := []string{fmt.Sprintf("ASSERTIONS: %s (%d/%d passed)", verdict, passed, len(results))}
+	for _, r := range results {
+		mark := "PASS"
+		if !r.Passed {
+			mark = "FAIL"
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s: %s", mark, r.Assertion, r.Detail))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// assertionRow is a single row of the assertion editor
+type assertionRow struct {
+	kind  *widget.Select
+	field *widget.Entry
+	value *widget.Entry
+	row   *fyne.Container
+}
+
+// assertionEditor edits the assertions of a request
+type assertionEditor struct {
+	rows    []*assertionRow
+	list    *fyne.Container
+	content *fyne.Container
+}
+
+// newAssertionEditor creates an empty assertion editor
+func newAssertionEditor() *assertionEditor {
+	e := &assertionEditor{list: container.NewVBox()}
+
+	addBtn := widget.NewButtonWithIcon("Add Assertion", theme.ContentAddIcon(), func() {
+		e.addRow(Assertion{Type: AssertStatus, Value: "200"})
+	})
+
+	e.content = container.NewBorder(nil, addBtn, nil, nil, e.list)
+	return e
+}
+
+// addRow appends an assertion row
+func (e *assertionEditor) addRow(a Assertion) {
+	labels := make([]string, 0, len(assertionTypes))
+	for _, t := range assertionTypes {
+		labels = append(labels, assertionLabels[t])
+	}
+
+	r := &assertionRow{
+		field: widget.NewEntry(),
+		value: widget.NewEntry(),
+	}
+	r.field.SetText(a.Field)
+	r.value.SetText(a.Value)
+
+	// Only JSON and header assertions apply to a field
+	r.kind = widget.NewSelect(labels, func(selected string) {
+		switch assertionType(selected) {
+		case AssertJSONEquals, AssertJSONContains:
+			r.field.SetPlaceHolder(".order.status")
+			r.field.Enable()
+		case AssertHeader:
+			r.field.SetPlaceHolder("Header")
+			r.field.Enable()
+		default:
+			r.field.SetPlaceHolder("")
+			r.field.SetText("")
+			r.field.Disable()
+		}
+		switch assertionType(selected) {
+		case AssertStatus:
+			r.value.SetPlaceHolder("200")
+		case AssertRegex:
+			r.value.SetPlaceHolder("regular expression")
+		case AssertMaxLatency:
+			r.value.SetPlaceHolder("250ms")
+		default:
+			r.value.SetPlaceHolder("Value")
+		}
+	})
+	r.kind.SetSelected(assertionLabels[a.Type])
+
+	removeBtn := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+		e.removeRow(r)
+	})
+
+	r.row = container.NewBorder(nil, nil, nil, removeBtn,
+		container.NewGridWithColumns(3, r.kind, r.field, r.value))
+
+	e.rows = append(e.rows, r)
+	e.list.Add(r.row)
+}
+
+// assertionType returns the assertion type of an editor label
+func assertionType(label string) string {
+	for t, l := range assertionLabels {
+		if l == label {
+			return t
+		}
+	}
+	return ""
+}
+
+// removeRow removes an assertion row from the editor
+func (e *assertionEditor) removeRow(r *assertionRow) {
+	for i, existing := range e.rows {
+		if existing == r {
+			e.rows = append(e.rows[:i], e.rows[i+1:]...)
+			break
+		}
+	}
+	e.list.Remove(r.row)
+}
+
+// Assertions returns the edited assertions, or nil when none are set
+func (e *assertionEditor) Assertions() []Assertion {
+	var assertions []Assertion
+	for _, r := range e.rows {
+		t := assertionType(r.kind.Selected)
+		if t == "" {
+			continue
+		}
+		assertions = append(assertions, Assertion{
+			Type:  t,
+			Field: strings.TrimSpace(r.field.Text),
+			Value: r.value.Text,
+		})
+	}
+	return assertions
+}
+
+// SetAssertions replaces the edited assertions
+func (e *assertionEditor) SetAssertions(assertions []Assertion) {
+	e.rows = nil
+	e.list.RemoveAll()
+	for _, a := range assertions {
+		e.addRow(a)
+	}
+}
+
+// Widget returns the editor's canvas object
+func (e *assertionEditor) Widget() fyne.CanvasObject {
+	return e.content
+}
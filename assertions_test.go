@@ -0,0 +1,159 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// testResponse builds a request response for assertion tests
+func testResponse(payload string, header nats.Header) *nats.Msg {
+	return &nats.Msg{Subject: "_INBOX.test", Header: header, Data: []byte(payload)}
+}
+
+func TestEvaluateAssertion(t *testing.T) {
+	order := testResponse(
+		`{"order":{"status":"shipped","total":42,"id":"007"},"tags":["a","b"],"counts":[1,2],"ok":true,"name":"hello world"}`,
+		nats.Header{"Content-Type": {"application/json"}})
+	raw := testResponse("HELLO there", nats.Header{"x-trace": {"abc"}})
+	failed := testResponse("", nats.Header{"Status": {"404"}, "Nats-Service-Error-Code": {"500"}})
+	serviceError := testResponse("", nats.Header{"Nats-Service-Error-Code": {"500"}})
+
+	tests := []struct {
+		name      string
+		assertion Assertion
+		msg       *nats.Msg
+		latency   time.Duration
+		err       error
+		want      bool
+	}{
+		// Status
+		{"status 200", Assertion{Type: AssertStatus, Value: "200"}, order, 0, nil, true},
+		{"status trimmed", Assertion{Type: AssertStatus, Value: " 200 "}, order, 0, nil, true},
+		{"status header wins", Assertion{Type: AssertStatus, Value: "404"}, failed, 0, nil, true},
+		{"service error code", Assertion{Type: AssertStatus, Value: "500"}, serviceError, 0, nil, true},
+		{"status mismatch", Assertion{Type: AssertStatus, Value: "200"}, serviceError, 0, nil, false},
+		{"no responders", Assertion{Type: AssertStatus, Value: "503"}, nil, 0, nats.ErrNoResponders, true},
+		{"timeout has no status", Assertion{Type: AssertStatus, Value: "503"}, nil, 0, nats.ErrTimeout, false},
+
+		// Header
+		{"header name ignoring case", Assertion{Type: AssertHeader, Field: "content-type", Value: "application/json"}, order, 0, nil, true},
+		{"raw header key", Assertion{Type: AssertHeader, Field: "x-trace", Value: "abc"}, raw, 0, nil, true},
+		{"raw header key by canonical name", Assertion{Type: AssertHeader, Field: "X-Trace", Value: "abc"}, raw, 0, nil, true},
+		{"header value mismatch", Assertion{Type: AssertHeader, Field: "Content-Type", Value: "text/plain"}, order, 0, nil, false},
+		{"header missing", Assertion{Type: AssertHeader, Field: "Missing", Value: ""}, order, 0, nil, false},
+		{"header without response", Assertion{Type: AssertHeader, Field: "Content-Type", Value: "application/json"}, nil, 0, nats.ErrTimeout, false},
+
+		// JSON Equals
+		{"json string", Assertion{Type: AssertJSONEquals, Field: ".order.status", Value: "shipped"}, order, 0, nil, true},
+		{"json string mismatch", Assertion{Type: AssertJSONEquals, Field: ".order.status", Value: "pending"}, order, 0, nil, false},
+		{"json number numerically", Assertion{Type: AssertJSONEquals, Field: ".order.total", Value: "42.0"}, order, 0, nil, true},
+		{"json number mismatch", Assertion{Type: AssertJSONEquals, Field: ".order.total", Value: "41"}, order, 0, nil, false},
+		{"json string is not a number", Assertion{Type: AssertJSONEquals, Field: ".order.id", Value: "7"}, order, 0, nil, false},
+		{"json string digits", Assertion{Type: AssertJSONEquals, Field: ".order.id", Value: "007"}, order, 0, nil, true},
+		{"json bool", Assertion{Type: AssertJSONEquals, Field: ".ok", Value: "true"}, order, 0, nil, true},
+		{"json array index", Assertion{Type: AssertJSONEquals, Field: ".tags[1]", Value: "b"}, order, 0, nil, true},
+		{"json field missing", Assertion{Type: AssertJSONEquals, Field: ".order.missing", Value: ""}, order, 0, nil, false},
+		{"json bad path", Assertion{Type: AssertJSONEquals, Field: ".tags[x]", Value: "a"}, order, 0, nil, false},
+		{"not json", Assertion{Type: AssertJSONEquals, Field: ".a", Value: "1"}, raw, 0, nil, false},
+
+		// JSON Contains
+		{"array holds string", Assertion{Type: AssertJSONContains, Field: ".tags", Value: "b"}, order, 0, nil, true},
+		{"array lacks string", Assertion{Type: AssertJSONContains, Field: ".tags", Value: "c"}, order, 0, nil, false},
+		{"array holds number", Assertion{Type: AssertJSONContains, Field: ".counts", Value: "2.0"}, order, 0, nil, true},
+		{"string contains text", Assertion{Type: AssertJSONContains, Field: ".name", Value: "lo wo"}, order, 0, nil, true},
+		{"string lacks text", Assertion{Type: AssertJSONContains, Field: ".name", Value: "bye"}, order, 0, nil, false},
+
+		// Payload Matches
+		{"plain pattern", Assertion{Type: AssertRegex, Value: "^HEL+O"}, raw, 0, nil, true},
+		{"regex literal", Assertion{Type: AssertRegex, Value: "/^HELLO/"}, raw, 0, nil, true},
+		{"regex literal ignore case", Assertion{Type: AssertRegex, Value: "/^hello/i"}, raw, 0, nil, true},
+		{"regex literal case sensitive", Assertion{Type: AssertRegex, Value: "/^hello/"}, raw, 0, nil, false},
+		{"regex no match", Assertion{Type: AssertRegex, Value: "bye"}, raw, 0, nil, false},
+		{"invalid regex", Assertion{Type: AssertRegex, Value: "("}, raw, 0, nil, false},
+
+		// Max Latency
+		{"within latency", Assertion{Type: AssertMaxLatency, Value: "250ms"}, order, 100 * time.Millisecond, nil, true},
+		{"at latency limit", Assertion{Type: AssertMaxLatency, Value: "250ms"}, order, 250 * time.Millisecond, nil, true},
+		{"over latency", Assertion{Type: AssertMaxLatency, Value: "250ms"}, order, 300 * time.Millisecond, nil, false},
+		{"invalid duration", Assertion{Type: AssertMaxLatency, Value: "fast"}, order, 0, nil, false},
+		{"latency without response", Assertion{Type: AssertMaxLatency, Value: "1s"}, nil, 0, nats.ErrTimeout, false},
+
+		{"unknown type", Assertion{Type: "bogus", Value: "x"}, order, 0, nil, false},
+	}
+
+	for _, tt := range tests {
+		result := evaluateAssertion(tt.assertion, tt.msg, tt.latency, tt.err)
+		if result.Passed != tt.want {
+			t.Errorf("%s: %s got passed=%v (%s), want %v", tt.name, tt.assertion, result.Passed, result.Detail, tt.want)
+		}
+	}
+}
+
+func TestRequestPassed(t *testing.T) {
+	pass := AssertionResult{Passed: true}
+	fail := AssertionResult{}
+
+	tests := []struct {
+		name    string
+		results []AssertionResult
+		err     error
+		want    bool
+	}{
+		{"response without assertions", nil, nil, true},
+		{"error without assertions", nil, nats.ErrTimeout, false},
+		{"empty results", []AssertionResult{}, nil, true},
+		{"all passed", []AssertionResult{pass, pass}, nil, true},
+		{"one failed", []AssertionResult{pass, fail}, nil, false},
+		// An expected 503 passes although the request failed
+		{"assertions decide on error", []AssertionResult{pass}, nats.ErrNoResponders, true},
+	}
+
+	for _, tt := range tests {
+		if got := requestPassed(tt.results, tt.err); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAssertionMode(t *testing.T) {
+	checks := []Assertion{{Type: AssertStatus, Value: "200"}}
+
+	tests := []struct {
+		mode       string
+		assertions []Assertion
+		wantErr    bool
+	}{
+		{"Publish", nil, false},
+		{"Publish", checks, true},
+		{"Request-Reply", checks, false},
+		{"Request-Many", checks, false},
+	}
+
+	for _, tt := range tests {
+		err := checkAssertionMode(tt.mode, tt.assertions)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s with %d assertions: got error %v, want error %v", tt.mode, len(tt.assertions), err, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatAssertionResults(t *testing.T) {
+	results := []AssertionResult{
+		{Assertion: Assertion{Type: AssertStatus, Value: "200"}, Passed: true, Detail: "got 200"},
+		{Assertion: Assertion{Type: AssertJSONEquals, Field: ".status", Value: "shipped"}, Detail: "got pending"},
+	}
+
+	got := formatAssertionResults(results)
+	for _, want := range []string{
+		"ASSERTIONS: FAILED (1/2 passed)",
+		"PASS  status == 200: got 200",
+		"FAIL  .status == shipped: got pending",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatAssertionResults missing %q in:\n%s", want, got)
+		}
+	}
+}
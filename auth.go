@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// Authentication methods supported by connection profiles
+const (
+	AuthNone         = "none"
+	AuthUserPassword = "user_password"
+	AuthToken        = "token"
+	AuthCreds        = "creds"
+	AuthNKey         = "nkey"
+	AuthJWT          = "jwt"
+)
+
+// authMethodLabels maps authentication methods to their display names
+var authMethodLabels = map[string]string{
+	AuthNone:         "None",
+	AuthUserPassword: "Username & Password",
+	AuthToken:        "Token",
+	AuthCreds:        "Credentials File (.creds)",
+	AuthNKey:         "NKey Seed File",
+	AuthJWT:          "User JWT & Seed",
+}
+
+// authMethodOrder is the order authentication methods are offered in the UI
+var authMethodOrder = []string{AuthNone, AuthUserPassword, AuthToken, AuthCreds, AuthNKey, AuthJWT}
+
+// authMethodFromLabel returns the authentication method for a display name
+func authMethodFromLabel(label string) string {
+	for _, method := range authMethodOrder {
+		if authMethodLabels[method] == label {
+			return method
+		}
+	}
+	return AuthNone
+}
+
+// jwtClaims holds the user JWT claims shown when inspecting credentials
+type jwtClaims struct {
+	Name    string `json:"name"`
+	Subject string `json:"sub"`
+	Issuer  string `json:"iss"`
+}
+
+// decodeJWTClaims decodes the claims of a JWT without verifying its signature
+func decodeJWTClaims(token string) (*jwtClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %v", err)
+	}
+
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT claims: %v", err)
+	}
+	return &claims, nil
+}
+
+// userPublicKey returns the public key of a user seed
+func userPublicKey(kp nkeys.KeyPair) (string, error) {
+	defer kp.Wipe()
+
+	publicKey, err := kp.PublicKey()
+	if err != nil {
+		return "", fmt.Errorf("invalid seed: %v", err)
+	}
+	if !nkeys.IsValidPublicUserKey(publicKey) {
+		return "", fmt.Errorf("seed is not a user seed (public key %s)", publicKey)
+	}
+	return publicKey, nil
+}
+
+// describeUserJWT summarizes a user JWT and checks that it belongs to the public key
+func describeUserJWT(token, publicKey string) (string, error) {
+	claims, err := decodeJWTClaims(token)
+	if err != nil {
+		return "", err
+	}
+	if claims.Subject != publicKey {
+		return "", fmt.Errorf("JWT subject %s does not match seed public key %s", claims.Subject, publicKey)
+	}
+
+	summary := fmt.Sprintf("User: %s\nPublic Key: %s\nIssuer: %s", claims.Name, publicKey, claims.Issuer)
+	return summary, nil
+}
+
+// AuthSummary validates the profile's credentials and describes the identity they carry
+func (p *ConnectionProfile) AuthSummary() (string, error) {
+	switch p.Auth() {
+	case AuthNone:
+		return "No authentication", nil
+
+	case AuthUserPassword:
+		if p.Username == "" {
+			return "", fmt.Errorf("username cannot be empty")
+		}
+		return fmt.Sprintf("User: %s", p.Username), nil
+
+	case AuthToken:
+		if p.Token == "" {
+			return "", fmt.Errorf("token cannot be empty")
+		}
+		return "Bearer token", nil
+
+	case AuthCreds:
+		if p.CredsFile == "" {
+			return "", fmt.Errorf("credentials file cannot be empty")
+		}
+		contents, err := os.ReadFile(p.CredsFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read credentials file: %v", err)
+		}
+		token, err := nkeys.ParseDecoratedJWT(contents)
+		if err != nil {
+			return "", fmt.Errorf("invalid credentials file: %v", err)
+		}
+		kp, err := nkeys.ParseDecoratedUserNKey(contents)
+		if err != nil {
+			return "", fmt.Errorf("invalid credentials file: %v", err)
+		}
+		publicKey, err := userPublicKey(kp)
+		if err != nil {
+			return "", err
+		}
+		return describeUserJWT(token, publicKey)
+
+	case AuthNKey:
+		if p.NKeyFile == "" {
+			return "", fmt.Errorf("NKey seed file cannot be empty")
+		}
+		contents, err := os.ReadFile(p.NKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read NKey seed file: %v", err)
+		}
+		kp, err := nkeys.ParseDecoratedNKey(contents)
+		if err != nil {
+			return "", fmt.Errorf("invalid NKey seed file: %v", err)
+		}
+		publicKey, err := userPublicKey(kp)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Public Key: %s", publicKey), nil
+
+	case AuthJWT:
+		if p.UserJWT == "" || p.UserSeed == "" {
+			return "", fmt.Errorf("user JWT and seed cannot be empty")
+		}
+		kp, err := nkeys.FromSeed([]byte(strings.TrimSpace(p.UserSeed)))
+		if err != nil {
+			return "", fmt.Errorf("invalid user seed: %v", err)
+		}
+		publicKey, err := userPublicKey(kp)
+		if err != nil {
+			return "", err
+		}
+		return describeUserJWT(p.UserJWT, publicKey)
+
+	default:
+		return "", fmt.Errorf("unknown authentication method: %s", p.AuthMethod)
+	}
+}
+
+// authOptions builds the nats.go authentication options of the profile
+func (p *ConnectionProfile) authOptions() ([]nats.Option, error) {
+	switch p.Auth() {
+	case AuthUserPassword:
+		return []nats.Option{nats.UserInfo(p.Username, p.Password)}, nil
+	case AuthToken:
+		return []nats.Option{nats.Token(p.Token)}, nil
+	case AuthCreds:
+		return []nats.Option{nats.UserCredentials(p.CredsFile)}, nil
+	case AuthNKey:
+		opt, err := nats.NkeyOptionFromSeed(p.NKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load NKey seed: %v", err)
+		}
+		return []nats.Option{opt}, nil
+	case AuthJWT:
+		return []nats.Option{nats.UserJWTAndSeed(strings.TrimSpace(p.UserJWT), strings.TrimSpace(p.UserSeed))}, nil
+	}
+	return nil, nil
+}
+
+// authEditor holds the widgets used to edit a profile's authentication settings
+type authEditor struct {
+	method       *widget.Select
+	username     *widget.Entry
+	password     *widget.Entry
+	token        *widget.Entry
+	credsFile    *widget.Entry
+	nkeyFile     *widget.Entry
+	userJWT      *widget.Entry
+	userSeed     *widget.Entry
+	fields       *fyne.Container
+	fieldsByAuth map[string]fyne.CanvasObject
+	checkButton  *widget.Button
+	checkResult  *widget.Label
+}
+
+// newAuthEditor creates the authentication editor for a profile
+func newAuthEditor(window fyne.Window, profile ConnectionProfile) *authEditor {
+	e := &authEditor{
+		username:    widget.NewEntry(),
+		password:    widget.NewPasswordEntry(),
+		token:       widget.NewPasswordEntry(),
+		userJWT:     widget.NewMultiLineEntry(),
+		userSeed:    widget.NewPasswordEntry(),
+		fields:      container.NewStack(),
+		checkResult: widget.NewLabel(""),
+	}
+	e.checkResult.Wrapping = fyne.TextWrapBreak
+
+	e.username.SetPlaceHolder("Username")
+	e.username.SetText(profile.Username)
+	e.password.SetPlaceHolder("Password")
+	e.password.SetText(profile.Password)
+	e.token.SetPlaceHolder("Token")
+	e.token.SetText(profile.Token)
+	e.userJWT.SetPlaceHolder("User JWT (eyJ0eXAiOiJKV1Qi...)")
+	e.userJWT.Wrapping = fyne.TextWrapBreak
+	e.userJWT.SetText(profile.UserJWT)
+	e.userSeed.SetPlaceHolder("User seed (SU...)")
+	e.userSeed.SetText(profile.UserSeed)
+
+	var credsRow, nkeyRow fyne.CanvasObject
+	e.credsFile, credsRow = newFilePathEntry(window, "Credentials file (.creds)")
+	e.credsFile.SetText(profile.CredsFile)
+	e.nkeyFile, nkeyRow = newFilePathEntry(window, "NKey seed file (.nk)")
+	e.nkeyFile.SetText(profile.NKeyFile)
+
+	e.fieldsByAuth = map[string]fyne.CanvasObject{
+		AuthNone:         widget.NewLabel(""),
+		AuthUserPassword: container.NewVBox(e.username, e.password),
+		AuthToken:        e.token,
+		AuthCreds:        credsRow,
+		AuthNKey:         nkeyRow,
+		AuthJWT:          container.NewVBox(e.userJWT, e.userSeed),
+	}
+
+	labels := make([]string, 0, len(authMethodOrder))
+	for _, method := range authMethodOrder {
+		labels = append(labels, authMethodLabels[method])
+	}
+	e.method = widget.NewSelect(labels, func(selected string) {
+		e.fields.Objects = []fyne.CanvasObject{e.fieldsByAuth[authMethodFromLabel(selected)]}
+		e.fields.Refresh()
+		e.checkResult.SetText("")
+	})
+	e.method.SetSelected(authMethodLabels[profile.Auth()])
+
+	// Validate the credentials without connecting, showing the identity they carry
+	e.checkButton = widget.NewButton("Check Credentials", func() {
+		var checked ConnectionProfile
+		e.apply(&checked)
+		summary, err := checked.AuthSummary()
+		if err != nil {
+			e.checkResult.SetText(fmt.Sprintf("Error: %v", err))
+			return
+		}
+		e.checkResult.SetText(summary)
+	})
+
+	return e
+}
+
+// apply copies the edited authentication settings into the profile. Only the fields of
+// the selected method are kept, so secrets of other methods are not saved
+func (e *authEditor) apply(profile *ConnectionProfile) {
+	profile.AuthMethod = authMethodFromLabel(e.method.Selected)
+	profile.Username = ""
+	profile.Password = ""
+	profile.Token = ""
+	profile.CredsFile = ""
+	profile.NKeyFile = ""
+	profile.UserJWT = ""
+	profile.UserSeed = ""
+
+	switch profile.AuthMethod {
+	case AuthUserPassword:
+		profile.Username = e.username.Text
+		profile.Password = e.password.Text
+	case AuthToken:
+		profile.Token = e.token.Text
+	case AuthCreds:
+		profile.CredsFile = e.credsFile.Text
+	case AuthNKey:
+		profile.NKeyFile = e.nkeyFile.Text
+	case AuthJWT:
+		profile.UserJWT = strings.TrimSpace(e.userJWT.Text)
+		profile.UserSeed = strings.TrimSpace(e.userSeed.Text)
+	}
+}
+
+// formItems returns the form rows of the authentication editor
+func (e *authEditor) formItems() []*widget.FormItem {
+	return []*widget.FormItem{
+		widget.NewFormItem("Auth", e.method),
+		widget.NewFormItem("Credentials", e.fields),
+		widget.NewFormItem("", container.NewVBox(e.checkButton, e.checkResult)),
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// messageCapture appends captured messages to a JSON Lines file on disk
+type messageCapture struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	count  int
+}
+
+// getCaptureDir returns the directory holding on-disk message captures
+func getCaptureDir() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	captureDir := filepath.Join(configDir, "captures")
+	if err := os.MkdirAll(captureDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create capture directory: %v", err)
+	}
+	return captureDir, nil
+}
+
+// openCapture creates a new capture file for a connection
+func openCapture(name string) (*messageCapture, error) {
+	captureDir, err := getCaptureDir()
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("%s_%s.jsonl", time.Now().Format("20060102-150405"), unsafeFileChars.ReplaceAllString(name, "_"))
+	path := filepath.Join(captureDir, fileName)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %v", err)
+	}
+
+	return &messageCapture{
+		path:   path,
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+// Write appends a message to the capture file, in the export format
+func (c *messageCapture) Write(msg *CapturedMessage) error {
+	data, err := json.Marshal(newExportRecord(msg))
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := c.writer.Write(data); err != nil {
+		return err
+	}
+	c.count++
+	return nil
+}
+
+// Flush writes buffered messages to disk
+func (c *messageCapture) Flush() error {
+	return c.writer.Flush()
+}
+
+// Close flushes and closes the capture file
+func (c *messageCapture) Close() error {
+	err := c.writer.Flush()
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// captureMessageLocked writes a message to the capture file when disk capture is enabled (must be called with lock held)
+func (nc *NATSClient) captureMessageLocked(msg *CapturedMessage, retention RetentionSettings) {
+	if !retention.CaptureToDisk || nc.captureFailed {
+		return
+	}
+
+	if nc.capture == nil {
+		capture, err := openCapture(fmt.Sprintf("%d_%s", nc.id, nc.profile.Name))
+		if err != nil {
+			nc.failCaptureLocked(err)
+			return
+		}
+		nc.capture = capture
+	}
+
+	if err := nc.capture.Write(msg); err != nil {
+		nc.failCaptureLocked(fmt.Errorf("failed to write capture file %s: %v", nc.capture.path, err))
+	}
+}
+
+// failCaptureLocked stops disk capture after an error and reports it once; the buffered
+// writer would fail every later write the same way (must be called with lock held)
+func (nc *NATSClient) failCaptureLocked(err error) {
+	if nc.capture != nil {
+		nc.capture.file.Close()
+		nc.capture = nil
+	}
+	nc.captureFailed = true
+
+	log.Printf("Disk capture disabled for this connection: %v", err)
+	nc.setLastError(fmt.Errorf("disk capture stopped: %v", err))
+	nc.recordEventLocked(EventError, "Disk capture stopped: %v", err)
+}
+
+// flushCaptureLocked writes buffered captured messages to disk (must be called with lock held)
+func (nc *NATSClient) flushCaptureLocked() {
+	if nc.capture == nil {
+		return
+	}
+	if err := nc.capture.Flush(); err != nil {
+		nc.failCaptureLocked(fmt.Errorf("failed to write capture file %s: %v", nc.capture.path, err))
+	}
+}
+
+// closeCaptureLocked closes the capture file, the next captured message starts a new one
+// and a failed capture is retried (must be called with lock held)
+func (nc *NATSClient) closeCaptureLocked() {
+	nc.captureFailed = false
+	if nc.capture == nil {
+		return
+	}
+	if err := nc.capture.Close(); err != nil {
+		log.Printf("Failed to close capture file %s: %v", nc.capture.path, err)
+	}
+	nc.capture = nil
+}
+
+// CloseCapture closes the connection's capture file
+func (nc *NATSClient) CloseCapture() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.closeCaptureLocked()
+}
+
+// CaptureFile returns the path of the current capture file and the number of messages written to it
+func (nc *NATSClient) CaptureFile() (string, int) {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	if nc.capture == nil {
+		return "", 0
+	}
+	return nc.capture.path, nc.capture.count
+}
@@ -0,0 +1,948 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// noEnvironment is the environment selector option for using only the global variables
+const noEnvironment = "(no environment)"
+
+// SavedRequest is a publish or request-reply operation saved in a collection
+type SavedRequest struct {
+	Name    string      `json:"name"`
+	Mode    string      `json:"mode"`
+	Subject string      `json:"subject"`
+	Headers nats.Header `json:"headers,omitempty"`
+	Body    string      `json:"body"`
+	Timeout string      `json:"timeout,omitempty"`
+	// MaxResponses and IdleGap stop Request-Many requests collecting responses
+	MaxResponses int    `json:"max_responses,omitempty"`
+	IdleGap      string `json:"idle_gap,omitempty"`
+	// Expand enables placeholder expansion when the request is sent
+	Expand bool `json:"expand,omitempty"`
+	// Assertions are checked against the response of request-reply requests
+	Assertions []Assertion `json:"assertions,omitempty"`
+}
+
+// CollectionFolder groups saved requests inside a collection
+type CollectionFolder struct {
+	Name     string         `json:"name"`
+	Requests []SavedRequest `json:"requests"`
+}
+
+// Collection is a named set of saved requests, shared as a JSON file
+type Collection struct {
+	Name     string             `json:"name"`
+	Folders  []CollectionFolder `json:"folders,omitempty"`
+	Requests []SavedRequest     `json:"requests,omitempty"`
+}
+
+// Environment is a named set of variables substituted into saved requests
+type Environment struct {
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+// collectionPath locates a collection, folder or request in the collections tree.
+// Folder and Request are -1 when the path does not point into one
+type collectionPath struct {
+	Collection int
+	Folder     int
+	Request    int
+}
+
+// ID returns the tree node ID of the path
+func (p collectionPath) ID() string {
+	id := "c" + strconv.Itoa(p.Collection)
+	if p.Folder >= 0 {
+		id += "/f" + strconv.Itoa(p.Folder)
+	}
+	if p.Request >= 0 {
+		id += "/r" + strconv.Itoa(p.Request)
+	}
+	return id
+}
+
+// parseCollectionPath parses a tree node ID
+func parseCollectionPath(id string) (collectionPath, bool) {
+	path := collectionPath{Collection: -1, Folder: -1, Request: -1}
+	for _, part := range strings.Split(id, "/") {
+		if len(part) < 2 {
+			return path, false
+		}
+		index, err := strconv.Atoi(part[1:])
+		if err != nil {
+			return path, false
+		}
+		switch part[0] {
+		case 'c':
+			path.Collection = index
+		case 'f':
+			path.Folder = index
+		case 'r':
+			path.Request = index
+		default:
+			return path, false
+		}
+	}
+	return path, path.Collection >= 0
+}
+
+// GetCollections returns the saved collections
+func (nc *NATSClient) GetCollections() []Collection {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return append([]Collection{}, nc.config.Collections...)
+}
+
+// collectionsSnapshot encodes the collections, to tell whether another tab changed them
+func (nc *NATSClient) collectionsSnapshot() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	data, _ := json.Marshal(nc.config.Collections)
+	return string(data)
+}
+
+// collectionRequestsLocked returns the requests of the collection or folder at a path
+func (nc *NATSClient) collectionRequestsLocked(path collectionPath) (*[]SavedRequest, error) {
+	if path.Collection < 0 || path.Collection >= len(nc.config.Collections) {
+		return nil, fmt.Errorf("collection not found")
+	}
+	collection := &nc.config.Collections[path.Collection]
+	if path.Folder < 0 {
+		return &collection.Requests, nil
+	}
+	if path.Folder >= len(collection.Folders) {
+		return nil, fmt.Errorf("folder not found")
+	}
+	return &collection.Folders[path.Folder].Requests, nil
+}
+
+// CollectionItem returns the name of the item at a path, and the request if it points to one
+func (nc *NATSClient) CollectionItem(path collectionPath) (string, *SavedRequest, bool) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	if path.Collection < 0 || path.Collection >= len(nc.config.Collections) {
+		return "", nil, false
+	}
+	collection := nc.config.Collections[path.Collection]
+	if path.Request < 0 && path.Folder < 0 {
+		return collection.Name, nil, true
+	}
+
+	requests := collection.Requests
+	if path.Folder >= 0 {
+		if path.Folder >= len(collection.Folders) {
+			return "", nil, false
+		}
+		if path.Request < 0 {
+			return collection.Folders[path.Folder].Name, nil, true
+		}
+		requests = collection.Folders[path.Folder].Requests
+	}
+	if path.Request >= len(requests) {
+		return "", nil, false
+	}
+	request := requests[path.Request]
+	return request.Name, &request, true
+}
+
+// CollectionChildren returns the tree node IDs below a node
+func (nc *NATSClient) CollectionChildren(id string) []string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	var ids []string
+	if id == "" {
+		for i := range nc.config.Collections {
+			ids = append(ids, collectionPath{Collection: i, Folder: -1, Request: -1}.ID())
+		}
+		return ids
+	}
+
+	path, ok := parseCollectionPath(id)
+	if !ok || path.Request >= 0 || path.Collection >= len(nc.config.Collections) {
+		return nil
+	}
+	collection := nc.config.Collections[path.Collection]
+
+	if path.Folder < 0 {
+		for i := range collection.Folders {
+			ids = append(ids, collectionPath{Collection: path.Collection, Folder: i, Request: -1}.ID())
+		}
+		for i := range collection.Requests {
+			ids = append(ids, collectionPath{Collection: path.Collection, Folder: -1, Request: i}.ID())
+		}
+		return ids
+	}
+
+	if path.Folder < len(collection.Folders) {
+		for i := range collection.Folders[path.Folder].Requests {
+			ids = append(ids, collectionPath{Collection: path.Collection, Folder: path.Folder, Request: i}.ID())
+		}
+	}
+	return ids
+}
+
+// CollectionRequests returns the requests at a path in tree order: a single request, the
+// requests of a folder, or the requests of every folder of a collection and its own
+func (nc *NATSClient) CollectionRequests(path collectionPath) []SavedRequest {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	if path.Collection < 0 || path.Collection >= len(nc.config.Collections) {
+		return nil
+	}
+	collection := nc.config.Collections[path.Collection]
+
+	// The requests directly in the collection or folder
+	requests := collection.Requests
+	if path.Folder >= 0 {
+		if path.Folder >= len(collection.Folders) {
+			return nil
+		}
+		requests = collection.Folders[path.Folder].Requests
+	}
+
+	if path.Request >= 0 {
+		if path.Request >= len(requests) {
+			return nil
+		}
+		return []SavedRequest{requests[path.Request]}
+	}
+	if path.Folder >= 0 {
+		return append([]SavedRequest{}, requests...)
+	}
+
+	// A whole collection runs its folders first, as listed in the tree
+	var all []SavedRequest
+	for _, f := range collection.Folders {
+		all = append(all, f.Requests...)
+	}
+	return append(all, requests...)
+}
+
+// RunCollectionRequest sends a saved request from this connection, expanding its
+// placeholders, and reports whether it passed its assertions
+func (nc *NATSClient) RunCollectionRequest(request SavedRequest) (bool, error) {
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return false, fmt.Errorf("%s: not connected to NATS server", request.Name)
+	}
+
+	subject, body, header := request.Subject, request.Body, request.Headers
+	if request.Expand {
+		var err error
+		subject, body, header, err = nc.ExpandMessage(subject, body, header)
+		if err != nil {
+			return false, fmt.Errorf("%s: template expansion failed: %v", request.Name, err)
+		}
+	}
+
+	if err := checkAssertionMode(request.Mode, request.Assertions); err != nil {
+		return false, fmt.Errorf("%s: %v", request.Name, err)
+	}
+
+	if request.Mode == "Publish" {
+		if err := nc.Publish(subject, body, header); err != nil {
+			return false, fmt.Errorf("%s: publish failed: %v", request.Name, err)
+		}
+		return true, nil
+	}
+
+	if request.Mode == "Request-Many" {
+		opts, err := parseRequestManyOptions(request.Timeout, strconv.Itoa(request.MaxResponses), request.IdleGap)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", request.Name, err)
+		}
+		passed, err := nc.RequestMany(nc, subject, body, header, opts, request.Assertions)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", request.Name, err)
+		}
+		return passed, nil
+	}
+
+	timeout := 5 * time.Second
+	if request.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(request.Timeout); err != nil {
+			return false, fmt.Errorf("%s: invalid timeout format: %v", request.Name, err)
+		}
+	}
+
+	// Request errors are recorded in the response output
+	results, err := nc.RunRequest(nc, subject, body, header, timeout, request.Assertions)
+	return requestPassed(results, err), nil
+}
+
+// runCollection runs saved requests one after another and reports the results
+func runCollection(client *NATSClient, window fyne.Window, name string, requests []SavedRequest) {
+	if len(requests) == 0 {
+		dialog.ShowError(fmt.Errorf("%s has no saved requests", name), window)
+		return
+	}
+
+	go func() {
+		var failed []string
+		for _, request := range requests {
+			passed, err := client.RunCollectionRequest(request)
+			if err != nil {
+				log.Printf("Run %s: %v", name, err)
+				client.addResponse(fmt.Sprintf("[%s] RUN: %s\nERROR: %v\n%s",
+					time.Now().Format("15:04:05"), request.Name, err, strings.Repeat("-", 50)))
+			}
+			if !passed {
+				failed = append(failed, request.Name)
+			}
+		}
+
+		summary := fmt.Sprintf("%s: %d of %d requests passed", name, len(requests)-len(failed), len(requests))
+		if len(failed) > 0 {
+			summary += "\n\nFailed: " + strings.Join(failed, ", ")
+		}
+		client.addResponse(fmt.Sprintf("[%s] RUN %s\n%s",
+			time.Now().Format("15:04:05"), summary, strings.Repeat("=", 50)))
+
+		fyne.Do(func() {
+			if len(failed) > 0 {
+				dialog.ShowError(fmt.Errorf("%s", summary), window)
+			} else {
+				dialog.ShowInformation("Run Passed", summary, window)
+			}
+		})
+	}()
+}
+
+// AddCollection creates an empty collection
+func (nc *NATSClient) AddCollection(name string) error {
+	if name == "" {
+		return fmt.Errorf("collection name cannot be empty")
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for _, c := range nc.config.Collections {
+		if c.Name == name {
+			return fmt.Errorf("collection %s already exists", name)
+		}
+	}
+	nc.config.Collections = append(nc.config.Collections, Collection{Name: name})
+	return saveConfig(nc.config)
+}
+
+// AddCollectionFolder creates an empty folder in a collection
+func (nc *NATSClient) AddCollectionFolder(collection int, name string) error {
+	if name == "" {
+		return fmt.Errorf("folder name cannot be empty")
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if collection < 0 || collection >= len(nc.config.Collections) {
+		return fmt.Errorf("collection not found")
+	}
+	c := &nc.config.Collections[collection]
+	for _, f := range c.Folders {
+		if f.Name == name {
+			return fmt.Errorf("folder %s already exists in %s", name, c.Name)
+		}
+	}
+	c.Folders = append(c.Folders, CollectionFolder{Name: name})
+	return saveConfig(nc.config)
+}
+
+// SaveCollectionRequest adds a request to the collection or folder at a path, replacing
+// a request with the same name
+func (nc *NATSClient) SaveCollectionRequest(path collectionPath, request SavedRequest) error {
+	if request.Name == "" {
+		return fmt.Errorf("request name cannot be empty")
+	}
+	if err := checkAssertionMode(request.Mode, request.Assertions); err != nil {
+		return err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	requests, err := nc.collectionRequestsLocked(path)
+	if err != nil {
+		return err
+	}
+	for i, existing := range *requests {
+		if existing.Name == request.Name {
+			(*requests)[i] = request
+			return saveConfig(nc.config)
+		}
+	}
+	*requests = append(*requests, request)
+	return saveConfig(nc.config)
+}
+
+// DeleteCollectionItem removes the collection, folder or request at a path
+func (nc *NATSClient) DeleteCollectionItem(path collectionPath) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if path.Collection < 0 || path.Collection >= len(nc.config.Collections) {
+		return fmt.Errorf("collection not found")
+	}
+
+	switch {
+	case path.Request >= 0:
+		requests, err := nc.collectionRequestsLocked(path)
+		if err != nil {
+			return err
+		}
+		if path.Request >= len(*requests) {
+			return fmt.Errorf("request not found")
+		}
+		*requests = append((*requests)[:path.Request], (*requests)[path.Request+1:]...)
+	case path.Folder >= 0:
+		c := &nc.config.Collections[path.Collection]
+		if path.Folder >= len(c.Folders) {
+			return fmt.Errorf("folder not found")
+		}
+		c.Folders = append(c.Folders[:path.Folder], c.Folders[path.Folder+1:]...)
+	default:
+		nc.config.Collections = append(nc.config.Collections[:path.Collection], nc.config.Collections[path.Collection+1:]...)
+	}
+	return saveConfig(nc.config)
+}
+
+// HasCollection reports whether a collection with the given name exists
+func (nc *NATSClient) HasCollection(name string) bool {
+	for _, c := range nc.GetCollections() {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportCollection adds a collection, replacing a collection with the same name
+func (nc *NATSClient) ImportCollection(collection Collection) error {
+	if collection.Name == "" {
+		return fmt.Errorf("collection has no name")
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, c := range nc.config.Collections {
+		if c.Name == collection.Name {
+			nc.config.Collections[i] = collection
+			return saveConfig(nc.config)
+		}
+	}
+	nc.config.Collections = append(nc.config.Collections, collection)
+	return saveConfig(nc.config)
+}
+
+// GetEnvironments returns the saved environments
+func (nc *NATSClient) GetEnvironments() []Environment {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return append([]Environment{}, nc.config.Environments...)
+}
+
+// GetEnvironment returns the environment with the given name
+func (nc *NATSClient) GetEnvironment(name string) (Environment, bool) {
+	for _, env := range nc.GetEnvironments() {
+		if env.Name == name {
+			return env, true
+		}
+	}
+	return Environment{}, false
+}
+
+// GetEnvironmentNames returns the environment selector options
+func (nc *NATSClient) GetEnvironmentNames() []string {
+	names := []string{noEnvironment}
+	for _, env := range nc.GetEnvironments() {
+		names = append(names, env.Name)
+	}
+	return names
+}
+
+// SaveEnvironment adds or replaces an environment
+func (nc *NATSClient) SaveEnvironment(env Environment) error {
+	if env.Name == "" || env.Name == noEnvironment {
+		return fmt.Errorf("invalid environment name")
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, existing := range nc.config.Environments {
+		if existing.Name == env.Name {
+			nc.config.Environments[i] = env
+			return saveConfig(nc.config)
+		}
+	}
+	nc.config.Environments = append(nc.config.Environments, env)
+	return saveConfig(nc.config)
+}
+
+// DeleteEnvironment removes an environment, deactivating it if it was active
+func (nc *NATSClient) DeleteEnvironment(name string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, env := range nc.config.Environments {
+		if env.Name == name {
+			nc.config.Environments = append(nc.config.Environments[:i], nc.config.Environments[i+1:]...)
+			if nc.config.ActiveEnvironment == name {
+				nc.config.ActiveEnvironment = ""
+			}
+			return saveConfig(nc.config)
+		}
+	}
+	return fmt.Errorf("environment %s not found", name)
+}
+
+// ActiveEnvironment returns the name of the active environment, empty when none is
+func (nc *NATSClient) ActiveEnvironment() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return nc.config.ActiveEnvironment
+}
+
+// SetActiveEnvironment switches the environment substituted into sent messages
+func (nc *NATSClient) SetActiveEnvironment(name string) {
+	if name == noEnvironment {
+		name = ""
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if nc.config.ActiveEnvironment == name {
+		return
+	}
+	nc.config.ActiveEnvironment = name
+	saveConfigAsync(nc.config)
+}
+
+// ActiveVariables returns the global template variables overridden by the active environment
+func (nc *NATSClient) ActiveVariables() map[string]string {
+	vars := nc.GetTemplateVariables()
+
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	for _, env := range nc.config.Environments {
+		if env.Name == nc.config.ActiveEnvironment {
+			for name, value := range env.Variables {
+				vars[name] = value
+			}
+		}
+	}
+	return vars
+}
+
+// showEnvironmentDialog creates or edits an environment
+func showEnvironmentDialog(client *NATSClient, window fyne.Window, env *Environment, onSaved func(name string)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. staging")
+
+	varsEntry := widget.NewMultiLineEntry()
+	varsEntry.SetPlaceHolder("name=value, one per line")
+	varsEntry.SetMinRowsVisible(8)
+
+	title := "New Environment"
+	if env != nil {
+		title = "Edit Environment"
+		nameEntry.SetText(env.Name)
+		nameEntry.Disable()
+		varsEntry.SetText(formatVariables(env.Variables))
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Variables", varsEntry),
+	}
+
+	envDialog := dialog.NewForm(title, "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		vars, err := parseVariables(varsEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		name := strings.TrimSpace(nameEntry.Text)
+		if env == nil {
+			if _, exists := client.GetEnvironment(name); exists {
+				dialog.ShowError(fmt.Errorf("environment %s already exists", name), window)
+				return
+			}
+		}
+		if err := client.SaveEnvironment(Environment{Name: name, Variables: vars}); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		onSaved(name)
+	}, window)
+	envDialog.Resize(fyne.NewSize(500, 0))
+	envDialog.Show()
+}
+
+// exportCollection writes a collection to a JSON file chosen by the user
+func exportCollection(collection Collection, window fyne.Window) {
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("export failed: %v", err), window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			dialog.ShowError(fmt.Errorf("export failed: %v", err), window)
+			return
+		}
+		dialog.ShowInformation("Export", fmt.Sprintf("Exported collection %s", collection.Name), window)
+	}, window)
+	saveDialog.SetFileName(collection.Name + ".json")
+	saveDialog.Show()
+}
+
+// importCollection reads a collection from a JSON file chosen by the user
+func importCollection(client *NATSClient, window fyne.Window, onImported func()) {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		var collection Collection
+		if err := json.NewDecoder(reader).Decode(&collection); err != nil {
+			dialog.ShowError(fmt.Errorf("import failed: %v", err), window)
+			return
+		}
+		if collection.Name == "" {
+			dialog.ShowError(fmt.Errorf("import failed: %s is not a collection", reader.URI().Name()), window)
+			return
+		}
+
+		doImport := func() {
+			if err := client.ImportCollection(collection); err != nil {
+				dialog.ShowError(fmt.Errorf("import failed: %v", err), window)
+				return
+			}
+			onImported()
+		}
+
+		if !client.HasCollection(collection.Name) {
+			doImport()
+			return
+		}
+		message := fmt.Sprintf("Collection %s already exists. Replace it?", collection.Name)
+		dialog.ShowConfirm("Import Collection", message, func(confirmed bool) {
+			if confirmed {
+				doImport()
+			}
+		}, window)
+	}, window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	openDialog.Show()
+}
+
+// showNameDialog asks for a name and passes it to onName
+func showNameDialog(title, initial string, window fyne.Window, onName func(name string)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(initial)
+	items := []*widget.FormItem{widget.NewFormItem("Name", nameEntry)}
+	dialog.ShowForm(title, "Save", "Cancel", items, func(confirmed bool) {
+		if confirmed {
+			onName(strings.TrimSpace(nameEntry.Text))
+		}
+	}, window)
+}
+
+// createCollectionsPanel creates the collections tree and environment selector of the
+// Publish tab. Selecting a saved request loads it into the publish controls
+func createCollectionsPanel(client *NATSClient, window fyne.Window) fyne.CanvasObject {
+	selected := collectionPath{Collection: -1, Folder: -1, Request: -1}
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			return client.CollectionChildren(id)
+		},
+		func(id widget.TreeNodeID) bool {
+			if id == "" {
+				return true
+			}
+			path, ok := parseCollectionPath(id)
+			return ok && path.Request < 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, label)
+		},
+		func(id widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			icon := row.Objects[1].(*widget.Icon)
+
+			path, _ := parseCollectionPath(id)
+			name, request, ok := client.CollectionItem(path)
+			switch {
+			case !ok:
+				icon.SetResource(nil)
+				label.SetText("")
+			case request == nil:
+				icon.SetResource(theme.FolderIcon())
+				label.SetText(name)
+			case request.Mode == "Publish":
+				icon.SetResource(theme.MailSendIcon())
+				label.SetText(name)
+			default:
+				icon.SetResource(theme.MailReplyIcon())
+				label.SetText(name)
+			}
+		},
+	)
+
+	tree.OnSelected = func(id widget.TreeNodeID) {
+		path, ok := parseCollectionPath(id)
+		if !ok {
+			return
+		}
+		selected = path
+		if _, request, ok := client.CollectionItem(path); ok && request != nil && client.loadRequestFunc != nil {
+			client.loadRequestFunc(*request)
+		}
+	}
+	tree.OnUnselected = func(id widget.TreeNodeID) {
+		selected = collectionPath{Collection: -1, Folder: -1, Request: -1}
+	}
+
+	// Collections are shared by all connection tabs. Changes are announced through the
+	// connection manager and redraw the tree of every tab, dropping the selected path
+	// whose indexes may now point at another item
+	shown := client.collectionsSnapshot()
+	refreshTree := func() {
+		shown = client.collectionsSnapshot()
+		selected = collectionPath{Collection: -1, Folder: -1, Request: -1}
+		tree.UnselectAll()
+		tree.Refresh()
+	}
+	client.manager.OnChange(client, func() {
+		// Connection changes keep the selection
+		if client.collectionsSnapshot() != shown {
+			refreshTree()
+		}
+	})
+
+	newCollectionBtn := widget.NewButtonWithIcon("Collection", theme.ContentAddIcon(), func() {
+		showNameDialog("New Collection", "", window, func(name string) {
+			if err := client.AddCollection(name); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			client.manager.Notify()
+		})
+	})
+
+	newFolderBtn := widget.NewButtonWithIcon("Folder", theme.FolderNewIcon(), func() {
+		if selected.Collection < 0 {
+			dialog.ShowError(fmt.Errorf("select a collection first"), window)
+			return
+		}
+		collection := selected.Collection
+		showNameDialog("New Folder", "", window, func(name string) {
+			if err := client.AddCollectionFolder(collection, name); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			client.manager.Notify()
+			tree.OpenBranch(collectionPath{Collection: collection, Folder: -1, Request: -1}.ID())
+		})
+	})
+
+	saveRequestBtn := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		if selected.Collection < 0 {
+			dialog.ShowError(fmt.Errorf("select a collection or folder to save the request in"), window)
+			return
+		}
+		if client.currentRequestFunc == nil {
+			return
+		}
+
+		// Save next to the selected request, under its name by default
+		target := selected
+		name := ""
+		if target.Request >= 0 {
+			name, _, _ = client.CollectionItem(target)
+			target.Request = -1
+		}
+
+		showNameDialog("Save Request", name, window, func(name string) {
+			request, err := client.currentRequestFunc()
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			request.Name = name
+			if err := client.SaveCollectionRequest(target, request); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			client.manager.Notify()
+			tree.OpenBranch(collectionPath{Collection: target.Collection, Folder: -1, Request: -1}.ID())
+			if target.Folder >= 0 {
+				tree.OpenBranch(target.ID())
+			}
+		})
+	})
+
+	deleteBtn := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected.Collection < 0 {
+			return
+		}
+		path := selected
+		name, _, ok := client.CollectionItem(path)
+		if !ok {
+			return
+		}
+		dialog.ShowConfirm("Delete", fmt.Sprintf("Delete %s?", name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := client.DeleteCollectionItem(path); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			client.manager.Notify()
+		}, window)
+	})
+
+	runBtn := widget.NewButtonWithIcon("Run", theme.MediaPlayIcon(), func() {
+		if selected.Collection < 0 {
+			dialog.ShowError(fmt.Errorf("select a collection, folder or request to run"), window)
+			return
+		}
+		name, _, ok := client.CollectionItem(selected)
+		if !ok {
+			return
+		}
+		runCollection(client, window, name, client.CollectionRequests(selected))
+	})
+
+	importBtn := widget.NewButtonWithIcon("Import", theme.FolderOpenIcon(), func() {
+		importCollection(client, window, client.manager.Notify)
+	})
+
+	exportBtn := widget.NewButtonWithIcon("Export", theme.DocumentSaveIcon(), func() {
+		collections := client.GetCollections()
+		if selected.Collection < 0 || selected.Collection >= len(collections) {
+			dialog.ShowError(fmt.Errorf("select a collection to export"), window)
+			return
+		}
+		exportCollection(collections[selected.Collection], window)
+	})
+
+	// === Environments ===
+	envSelect := widget.NewSelect(client.GetEnvironmentNames(), func(selected string) {
+		if selected == noEnvironment {
+			selected = ""
+		}
+		if selected == client.ActiveEnvironment() {
+			return
+		}
+		client.SetActiveEnvironment(selected)
+		// The active environment is shared by all connection tabs
+		client.manager.Notify()
+	})
+	showEnvironments := func(name string) {
+		envSelect.SetOptions(client.GetEnvironmentNames())
+		if name == "" {
+			name = noEnvironment
+		}
+		envSelect.SetSelected(name)
+	}
+	showEnvironments(client.ActiveEnvironment())
+
+	// Show environments saved, deleted or activated in other tabs
+	client.manager.OnChange(client, func() {
+		showEnvironments(client.ActiveEnvironment())
+	})
+
+	// selectEnvironment shows the environment list after a change and selects the environment
+	selectEnvironment := func(name string) {
+		showEnvironments(name)
+		client.manager.Notify()
+	}
+
+	newEnvBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		showEnvironmentDialog(client, window, nil, selectEnvironment)
+	})
+	editEnvBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+		env, ok := client.GetEnvironment(envSelect.Selected)
+		if !ok {
+			dialog.ShowError(fmt.Errorf("select an environment to edit"), window)
+			return
+		}
+		showEnvironmentDialog(client, window, &env, selectEnvironment)
+	})
+	deleteEnvBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		name := envSelect.Selected
+		if _, ok := client.GetEnvironment(name); !ok {
+			return
+		}
+		dialog.ShowConfirm("Delete Environment", fmt.Sprintf("Delete environment %s?", name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := client.DeleteEnvironment(name); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			selectEnvironment("")
+		}, window)
+	})
+
+	envRow := container.NewBorder(nil, nil,
+		widget.NewLabel("Environment:"),
+		container.NewHBox(newEnvBtn, editEnvBtn, deleteEnvBtn),
+		envSelect,
+	)
+
+	buttons := container.NewGridWithColumns(4,
+		newCollectionBtn, newFolderBtn, saveRequestBtn, runBtn,
+		deleteBtn, importBtn, exportBtn,
+	)
+
+	return container.NewBorder(
+		container.NewVBox(envRow, widget.NewSeparator(), widget.NewLabel("Collections")),
+		buttons,
+		nil, nil,
+		tree,
+	)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// ConnectionManager tracks the NATS connections open in the window
+type ConnectionManager struct {
+	config    *AppConfig
+	clients   []*NATSClient
+	listeners []connectionListener
+	nextID    int
+	mu        sync.RWMutex
+}
+
+// connectionListener is notified when connections are added, removed or renamed
+type connectionListener struct {
+	owner *NATSClient
+	fn    func()
+}
+
+// NewConnectionManager creates a connection manager sharing the given configuration
+func NewConnectionManager(config *AppConfig) *ConnectionManager {
+	return &ConnectionManager{config: config}
+}
+
+// Add creates a new client managed by the connection manager
+func (m *ConnectionManager) Add() *NATSClient {
+	m.mu.Lock()
+	m.nextID++
+	client := NewNATSClient(m.config)
+	client.id = m.nextID
+	client.manager = m
+	m.clients = append(m.clients, client)
+	m.mu.Unlock()
+
+	m.Notify()
+	return client
+}
+
+// Remove disconnects a client and stops managing it
+func (m *ConnectionManager) Remove(client *NATSClient) {
+	client.Disconnect()
+	client.CloseCapture()
+
+	m.mu.Lock()
+	for i, c := range m.clients {
+		if c == client {
+			m.clients = append(m.clients[:i], m.clients[i+1:]...)
+			break
+		}
+	}
+
+	// Drop listeners registered by the client's UI
+	listeners := m.listeners[:0]
+	for _, l := range m.listeners {
+		if l.owner != client {
+			listeners = append(listeners, l)
+		}
+	}
+	m.listeners = listeners
+	m.mu.Unlock()
+
+	m.Notify()
+}
+
+// Clients returns the managed clients in the order they were opened
+func (m *ConnectionManager) Clients() []*NATSClient {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*NATSClient{}, m.clients...)
+}
+
+// Names returns the display names of the managed clients
+func (m *ConnectionManager) Names() []string {
+	clients := m.Clients()
+	names := make([]string, 0, len(clients))
+	for _, client := range clients {
+		names = append(names, client.DisplayName())
+	}
+	return names
+}
+
+// Find returns the managed client with the given display name
+func (m *ConnectionManager) Find(name string) *NATSClient {
+	for _, client := range m.Clients() {
+		if client.DisplayName() == name {
+			return client
+		}
+	}
+	return nil
+}
+
+// OnChange registers a callback for connection changes and changes to the shared
+// profiles and environments, owned by the given client's UI
+func (m *ConnectionManager) OnChange(owner *NATSClient, fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, connectionListener{owner: owner, fn: fn})
+}
+
+// Notify calls the registered change callbacks
+func (m *ConnectionManager) Notify() {
+	m.mu.RLock()
+	listeners := append([]connectionListener{}, m.listeners...)
+	m.mu.RUnlock()
+
+	for _, l := range listeners {
+		l.fn()
+	}
+}
+
+// Close saves the configuration and disconnects every client
+func (m *ConnectionManager) Close() {
+	configMu.Lock()
+	if err := saveConfig(m.config); err != nil {
+		log.Printf("Failed to save configuration on exit: %v", err)
+	}
+	configMu.Unlock()
+
+	for _, client := range m.Clients() {
+		client.Disconnect()
+		client.CloseCapture()
+	}
+}
+
+// DisplayName returns the name identifying the client in tabs and selectors
+func (nc *NATSClient) DisplayName() string {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	name := nc.profile.Name
+	if name == "" {
+		if servers := nc.profile.Servers(); len(servers) > 0 {
+			name = servers[0]
+		} else {
+			name = "New Connection"
+		}
+	}
+	return fmt.Sprintf("#%d %s", nc.id, name)
+}
+
+// containsClient reports whether client is in clients
+func containsClient(clients []*NATSClient, client *NATSClient) bool {
+	for _, c := range clients {
+		if c == client {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// Connection event kinds recorded in the event log
+const (
+	EventConnected     = "CONNECTED"
+	EventDisconnected  = "DISCONNECTED"
+	EventReconnecting  = "RECONNECTING"
+	EventReconnected   = "RECONNECTED"
+	EventClosed        = "CLOSED"
+	EventDiscovered    = "DISCOVERED"
+	EventLameDuck      = "LAME DUCK"
+	EventError         = "ERROR"
+	EventSlowConsumer  = "SLOW CONSUMER"
+	EventRestored      = "RESTORED"
+	maxConnectionEvent = 500
+)
+
+// ConnectionEvent is a single entry of the connection event log
+type ConnectionEvent struct {
+	Time    time.Time
+	Kind    string
+	Message string
+}
+
+// String formats the event for display
+func (e ConnectionEvent) String() string {
+	return fmt.Sprintf("[%s] %-13s %s", e.Time.Format("15:04:05.000"), e.Kind, e.Message)
+}
+
+// recordEvent appends an event to the connection event log
+func (nc *NATSClient) recordEvent(kind, format string, args ...interface{}) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.recordEventLocked(kind, format, args...)
+}
+
+// recordEventLocked appends an event to the connection event log (must be called with lock held)
+func (nc *NATSClient) recordEventLocked(kind, format string, args ...interface{}) {
+	event := ConnectionEvent{
+		Time:    time.Now(),
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	nc.events = append(nc.events, event)
+	if len(nc.events) > maxConnectionEvent {
+		nc.events = nc.events[len(nc.events)-maxConnectionEvent:]
+	}
+
+	// Newest events first
+	lines := make([]string, len(nc.events))
+	for i, e := range nc.events {
+		lines[len(nc.events)-1-i] = e.String()
+	}
+	nc.eventLines.Set(lines)
+}
+
+// GetEvents returns the connection event log
+func (nc *NATSClient) GetEvents() []ConnectionEvent {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return append([]ConnectionEvent{}, nc.events...)
+}
+
+// ClearEvents clears the connection event log
+func (nc *NATSClient) ClearEvents() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	nc.events = nil
+	nc.eventLines.Set([]string{})
+}
+
+// setLastError records the most recent connection error
+func (nc *NATSClient) setLastError(err error) {
+	if err == nil {
+		return
+	}
+	nc.lastError.Set(fmt.Sprintf("%s %v", time.Now().Format("15:04:05"), err))
+}
+
+// resetConnectionState resets the status model for a new connection
+func (nc *NATSClient) resetConnectionState() {
+	nc.serverURL.Set("")
+	nc.reconnectAttempts.Set(0)
+	nc.lastError.Set("")
+	nc.slowConsumers.Set(0)
+}
+
+// lifecycleOptions returns the options wiring nats.go connection callbacks into the event log
+func (nc *NATSClient) lifecycleOptions(profile ConnectionProfile) []nats.Option {
+	wait, _ := profile.reconnectWait()
+	jitter, _ := profile.reconnectJitter()
+
+	return []nats.Option{
+		nats.ConnectHandler(func(conn *nats.Conn) {
+			nc.reconnectAttempts.Set(0)
+			nc.serverURL.Set(conn.ConnectedUrlRedacted())
+			nc.status.Set("Connected")
+			nc.recordEvent(EventConnected, "Connected to %s", conn.ConnectedUrlRedacted())
+		}),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			if err != nil {
+				nc.setLastError(err)
+				nc.recordEvent(EventDisconnected, "Disconnected: %v", err)
+			} else {
+				nc.recordEvent(EventDisconnected, "Disconnected")
+			}
+			if conn.IsReconnecting() {
+				nc.status.Set("Reconnecting...")
+			}
+		}),
+		nats.CustomReconnectDelay(func(attempts int) time.Duration {
+			// Every server in the pool has been tried once per attempt
+			nc.reconnectAttempts.Set(attempts)
+			nc.status.Set(fmt.Sprintf("Reconnecting (attempt %d)", attempts))
+
+			delay := wait
+			if jitter > 0 {
+				delay += time.Duration(rand.Int63n(int64(jitter)))
+			}
+			nc.recordEvent(EventReconnecting, "Attempt %d failed, retrying in %s", attempts, delay.Round(time.Millisecond))
+			return delay
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			nc.mu.Lock()
+			first := nc.connecting
+			nc.connecting = false
+			nc.mu.Unlock()
+
+			nc.reconnectAttempts.Set(0)
+			nc.serverURL.Set(conn.ConnectedUrlRedacted())
+			nc.status.Set("Connected")
+			// A retried initial connect succeeds through the reconnect path
+			if first {
+				nc.recordEvent(EventConnected, "Connected to %s", conn.ConnectedUrlRedacted())
+				return
+			}
+			nc.recordEvent(EventReconnected, "Reconnected to %s (total reconnects: %d)",
+				conn.ConnectedUrlRedacted(), conn.Stats().Reconnects)
+		}),
+		nats.ClosedHandler(func(conn *nats.Conn) {
+			nc.mu.RLock()
+			userClosed := nc.userClosed
+			nc.mu.RUnlock()
+
+			nc.serverURL.Set("")
+			if userClosed {
+				nc.recordEvent(EventClosed, "Connection closed")
+				return
+			}
+
+			// The client gave up reconnecting
+			nc.status.Set("Closed")
+			if err := conn.LastError(); err != nil {
+				nc.setLastError(err)
+				nc.recordEvent(EventClosed, "Connection closed: %v", err)
+			} else {
+				nc.recordEvent(EventClosed, "Connection closed: reconnect attempts exhausted")
+			}
+		}),
+		nats.DiscoveredServersHandler(func(conn *nats.Conn) {
+			nc.recordEvent(EventDiscovered, "Discovered servers: %s", strings.Join(conn.DiscoveredServers(), ", "))
+		}),
+		nats.LameDuckModeHandler(func(conn *nats.Conn) {
+			nc.recordEvent(EventLameDuck, "Server %s entered lame duck mode", conn.ConnectedUrlRedacted())
+		}),
+		nats.ErrorHandler(func(conn *nats.Conn, sub *nats.Subscription, err error) {
+			subject := ""
+			if sub != nil {
+				subject = sub.Subject
+			}
+
+			nc.setLastError(err)
+			if errors.Is(err, nats.ErrSlowConsumer) {
+				count, _ := nc.slowConsumers.Get()
+				nc.slowConsumers.Set(count + 1)
+				nc.markSlowConsumer(sub)
+				nc.recordEvent(EventSlowConsumer, "Slow consumer on %s, messages are being dropped", subject)
+				return
+			}
+
+			if subject != "" {
+				nc.recordEvent(EventError, "%s: %v", subject, err)
+			} else {
+				nc.recordEvent(EventError, "%v", err)
+			}
+		}),
+	}
+}
+
+// createEventsTab creates the connection event log view
+func createEventsTab(client *NATSClient) *fyne.Container {
+	eventList := widget.NewListWithData(
+		client.eventLines,
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(item binding.DataItem, obj fyne.CanvasObject) {
+			obj.(*widget.Label).Bind(item.(binding.String))
+		},
+	)
+
+	clearBtn := widget.NewButton("Clear", func() {
+		client.ClearEvents()
+	})
+
+	eventCountLabel := widget.NewLabel("")
+	client.eventLines.AddListener(binding.NewDataListener(func() {
+		eventCountLabel.SetText(fmt.Sprintf("Events: %d", client.eventLines.Length()))
+	}))
+
+	header := container.NewBorder(
+		nil, nil,
+		eventCountLabel,
+		clearBtn,
+		nil,
+	)
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(header, widget.NewSeparator()),
+		nil, nil, nil,
+		eventList,
+	))
+}
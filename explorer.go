@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// explorerRefreshInterval is how often the subject tree and its rates are updated
+const explorerRefreshInterval = time.Second
+
+// maxSubjectNodes bounds the subject tree when subjects carry unique IDs
+const maxSubjectNodes = 10000
+
+// maxPreviewLength is the number of payload bytes kept as the last payload preview
+const maxPreviewLength = 200
+
+// subjectNode is a token of the subject hierarchy observed in captured messages
+type subjectNode struct {
+	Token string
+	// Path is the subject up to and including this token
+	Path string
+	// Count includes the messages of every subject below this node
+	Count int
+	// Direct counts the messages published to exactly this subject
+	Direct int
+	// Rate is the messages per second below this node, over the last refresh interval
+	Rate        float64
+	LastAt      time.Time
+	LastSubject string
+	LastPayload string
+	children    map[string]*subjectNode
+	rateCount   int
+}
+
+// subjectTree counts captured messages by subject token
+type subjectTree struct {
+	root      *subjectNode
+	nodes     map[string]*subjectNode
+	rateSince time.Time
+}
+
+// newSubjectTree creates an empty subject tree
+func newSubjectTree() *subjectTree {
+	return &subjectTree{
+		root:      &subjectNode{children: make(map[string]*subjectNode)},
+		nodes:     make(map[string]*subjectNode),
+		rateSince: time.Now(),
+	}
+}
+
+// observe counts a message on every node along its subject
+func (t *subjectTree) observe(msg *CapturedMessage) {
+	preview := string(msg.Data)
+	if len(preview) > maxPreviewLength {
+		preview = preview[:maxPreviewLength]
+	}
+	preview = strings.Join(strings.Fields(strings.ToValidUTF8(preview, "�")), " ")
+
+	node := t.root
+	t.touch(node, msg, preview)
+
+	path := ""
+	for _, token := range strings.Split(msg.Subject, ".") {
+		if path == "" {
+			path = token
+		} else {
+			path = path + "." + token
+		}
+
+		child, ok := node.children[token]
+		if !ok {
+			// Past the limit, deeper tokens are only counted on their ancestors
+			if len(t.nodes) >= maxSubjectNodes {
+				return
+			}
+			child = &subjectNode{Token: token, Path: path, children: make(map[string]*subjectNode)}
+			node.children[token] = child
+			t.nodes[path] = child
+		}
+		node = child
+		t.touch(node, msg, preview)
+	}
+	node.Direct++
+}
+
+// touch records a message on a single node
+func (t *subjectTree) touch(node *subjectNode, msg *CapturedMessage, preview string) {
+	node.Count++
+	node.LastAt = msg.ReceivedAt
+	node.LastSubject = msg.Subject
+	node.LastPayload = preview
+}
+
+// updateRates computes the message rates since the previous update
+func (t *subjectTree) updateRates(now time.Time) {
+	elapsed := now.Sub(t.rateSince).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	t.rateSince = now
+
+	update := func(node *subjectNode) {
+		node.Rate = float64(node.Count-node.rateCount) / elapsed
+		node.rateCount = node.Count
+	}
+	update(t.root)
+	for _, node := range t.nodes {
+		update(node)
+	}
+}
+
+// node returns the node at a path, the root for an empty path
+func (t *subjectTree) node(path string) *subjectNode {
+	if path == "" {
+		return t.root
+	}
+	return t.nodes[path]
+}
+
+// SubjectChildren returns the paths of the subject tokens observed below a path, sorted
+func (nc *NATSClient) SubjectChildren(path string) []string {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	node := nc.subjects.node(path)
+	if node == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(node.children))
+	for _, child := range node.children {
+		paths = append(paths, child.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// SubjectNode returns a copy of the subject tree node at a path
+func (nc *NATSClient) SubjectNode(path string) (subjectNode, bool) {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	node := nc.subjects.node(path)
+	if node == nil {
+		return subjectNode{}, false
+	}
+	copied := *node
+	copied.children = nil
+	return copied, true
+}
+
+// HasSubjectChildren reports whether tokens were observed below a path
+func (nc *NATSClient) HasSubjectChildren(path string) bool {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	node := nc.subjects.node(path)
+	return node != nil && len(node.children) > 0
+}
+
+// UpdateSubjectRates recomputes the message rates of the subject tree
+func (nc *NATSClient) UpdateSubjectRates() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.subjects.updateRates(time.Now())
+}
+
+// ClearSubjects resets the subject tree
+func (nc *NATSClient) ClearSubjects() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.subjects = newSubjectTree()
+}
+
+// formatRate formats a message rate
+func formatRate(rate float64) string {
+	if rate < 10 {
+		return fmt.Sprintf("%.1f/s", rate)
+	}
+	return fmt.Sprintf("%.0f/s", rate)
+}
+
+// createExplorerTab creates the live subject tree of the captured traffic. showTab
+// switches to another tab of the connection after pre-filling its controls
+func createExplorerTab(client *NATSClient, showTab func(name string)) *fyne.Container {
+	selected := ""
+
+	tree := widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			return client.SubjectChildren(uid)
+		},
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || client.HasSubjectChildren(uid)
+		},
+		func(branch bool) fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(uid widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			node, ok := client.SubjectNode(uid)
+			if !ok {
+				obj.(*widget.Label).SetText("")
+				return
+			}
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s  (%d msgs, %s)", node.Token, node.Count, formatRate(node.Rate)))
+		},
+	)
+
+	subjectLabel := widget.NewLabel("-")
+	subjectLabel.Wrapping = fyne.TextWrapBreak
+	countLabel := widget.NewLabel("-")
+	rateLabel := widget.NewLabel("-")
+	lastSeenLabel := widget.NewLabel("-")
+	previewLabel := widget.NewLabel("")
+	previewLabel.Wrapping = fyne.TextWrapBreak
+
+	subscribeBranchBtn := widget.NewButtonWithIcon("Subscribe to Branch", theme.DownloadIcon(), func() {
+		if client.prefillSubscribeFunc != nil {
+			client.prefillSubscribeFunc(selected + ".>")
+			showTab("Subscribe")
+		}
+	})
+	subscribeSubjectBtn := widget.NewButtonWithIcon("Subscribe to Subject", theme.DownloadIcon(), func() {
+		if client.prefillSubscribeFunc != nil {
+			client.prefillSubscribeFunc(selected)
+			showTab("Subscribe")
+		}
+	})
+	publishBtn := widget.NewButtonWithIcon("Publish to Subject", theme.MailSendIcon(), func() {
+		if client.prefillPublishFunc != nil {
+			client.prefillPublishFunc(selected)
+			showTab("Publish")
+		}
+	})
+
+	// Show the details of the selected node
+	updateDetails := func() {
+		node, ok := client.SubjectNode(selected)
+		if selected == "" || !ok {
+			subjectLabel.SetText("Select a subject token")
+			countLabel.SetText("-")
+			rateLabel.SetText("-")
+			lastSeenLabel.SetText("-")
+			previewLabel.SetText("")
+			subscribeBranchBtn.Disable()
+			subscribeSubjectBtn.Disable()
+			publishBtn.Disable()
+			return
+		}
+
+		subjectLabel.SetText(node.Path)
+		countLabel.SetText(fmt.Sprintf("%d (%d on this exact subject)", node.Count, node.Direct))
+		rateLabel.SetText(formatRate(node.Rate))
+		lastSeenLabel.SetText(fmt.Sprintf("%s on %s", node.LastAt.Format("15:04:05.000"), node.LastSubject))
+		previewLabel.SetText(node.LastPayload)
+		subscribeBranchBtn.Enable()
+		subscribeSubjectBtn.Enable()
+		publishBtn.Enable()
+	}
+
+	tree.OnSelected = func(uid widget.TreeNodeID) {
+		selected = uid
+		updateDetails()
+	}
+	tree.OnUnselected = func(uid widget.TreeNodeID) {
+		selected = ""
+		updateDetails()
+	}
+	updateDetails()
+
+	clearBtn := widget.NewButton("Clear", func() {
+		client.ClearSubjects()
+		tree.UnselectAll()
+		tree.Refresh()
+	})
+
+	// Update rates and redraw the tree while the connection tab is open
+	go func() {
+		ticker := time.NewTicker(explorerRefreshInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if !containsClient(client.manager.Clients(), client) {
+				return
+			}
+			client.UpdateSubjectRates()
+			fyne.Do(func() {
+				tree.Refresh()
+				updateDetails()
+			})
+		}
+	}()
+
+	details := widget.NewForm(
+		widget.NewFormItem("Subject", subjectLabel),
+		widget.NewFormItem("Messages", countLabel),
+		widget.NewFormItem("Rate", rateLabel),
+		widget.NewFormItem("Last Seen", lastSeenLabel),
+		widget.NewFormItem("Last Payload", previewLabel),
+	)
+
+	detailPane := container.NewBorder(
+		details,
+		container.NewVBox(subscribeBranchBtn, subscribeSubjectBtn, publishBtn),
+		nil, nil,
+	)
+
+	split := container.NewHSplit(tree, container.NewVScroll(detailPane))
+	split.SetOffset(0.5)
+
+	header := container.NewBorder(nil, nil,
+		widget.NewLabel("Subjects observed in captured messages (subscribe to > to see everything):"),
+		clearBtn,
+	)
+
+	return container.NewPadded(container.NewBorder(header, nil, nil, nil, split))
+}
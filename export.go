@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// Export formats
+const (
+	ExportJSONL = "JSON Lines"
+	ExportCSV   = "CSV"
+	ExportRaw   = "Raw payloads (one file per message)"
+)
+
+// Export scopes
+const (
+	ExportAll      = "All messages"
+	ExportFiltered = "Filtered messages"
+	ExportSelected = "Selected message"
+)
+
+// Payload encodings used in exported records
+const (
+	EncodingUTF8   = "utf-8"
+	EncodingBase64 = "base64"
+)
+
+// exportRecord is the JSON Lines representation of a captured message
+type exportRecord struct {
+	Seq          uint64      `json:"seq"`
+	Timestamp    time.Time   `json:"timestamp"`
+	Subject      string      `json:"subject"`
+	Reply        string      `json:"reply,omitempty"`
+	Headers      nats.Header `json:"headers,omitempty"`
+	Encoding     string      `json:"encoding"`
+	Payload      string      `json:"payload"`
+	Size         int         `json:"size"`
+	Subscription string      `json:"subscription,omitempty"`
+	Queue        string      `json:"queue,omitempty"`
+}
+
+// encodePayload encodes a payload as UTF-8 text when possible, base64 otherwise
+func encodePayload(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return EncodingUTF8, string(data)
+	}
+	return EncodingBase64, base64.StdEncoding.EncodeToString(data)
+}
+
+// newExportRecord converts a captured message to its exported form
+func newExportRecord(msg *CapturedMessage) exportRecord {
+	encoding, payload := encodePayload(msg.Data)
+	return exportRecord{
+		Seq:          msg.Seq,
+		Timestamp:    msg.ReceivedAt,
+		Subject:      msg.Subject,
+		Reply:        msg.Reply,
+		Headers:      msg.Header,
+		Encoding:     encoding,
+		Payload:      payload,
+		Size:         msg.Size(),
+		Subscription: msg.Subscription,
+		Queue:        msg.Queue,
+	}
+}
+
+// message converts an exported record back to a captured message
+func (r exportRecord) message() (*CapturedMessage, error) {
+	if r.Subject == "" {
+		return nil, fmt.Errorf("record has no subject")
+	}
+
+	data := []byte(r.Payload)
+	switch r.Encoding {
+	case EncodingUTF8, "":
+	case EncodingBase64:
+		decoded, err := base64.StdEncoding.DecodeString(r.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 payload: %v", err)
+		}
+		data = decoded
+	default:
+		return nil, fmt.Errorf("unknown payload encoding %q", r.Encoding)
+	}
+
+	return &CapturedMessage{
+		Subject:      r.Subject,
+		Reply:        r.Reply,
+		Header:       r.Headers,
+		Data:         data,
+		ReceivedAt:   r.Timestamp,
+		Subscription: r.Subscription,
+		Queue:        r.Queue,
+	}, nil
+}
+
+// writeJSONLines writes messages as one JSON record per line
+func writeJSONLines(w io.Writer, msgs []*CapturedMessage) error {
+	encoder := json.NewEncoder(w)
+	for _, msg := range msgs {
+		if err := encoder.Encode(newExportRecord(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readJSONLines reads messages written by writeJSONLines
+func readJSONLines(r io.Reader) ([]*CapturedMessage, error) {
+	var msgs []*CapturedMessage
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record exportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		msg, err := record.message()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// writeCSV writes messages as CSV with a header row
+func writeCSV(w io.Writer, msgs []*CapturedMessage) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"seq", "timestamp", "subject", "reply", "subscription", "queue", "size", "headers", "encoding", "payload"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		record := newExportRecord(msg)
+
+		headers := ""
+		if len(msg.Header) > 0 {
+			encoded, err := json.Marshal(msg.Header)
+			if err != nil {
+				return err
+			}
+			headers = string(encoded)
+		}
+
+		row := []string{
+			strconv.FormatUint(record.Seq, 10),
+			record.Timestamp.Format(time.RFC3339Nano),
+			record.Subject,
+			record.Reply,
+			record.Subscription,
+			record.Queue,
+			strconv.Itoa(record.Size),
+			headers,
+			record.Encoding,
+			record.Payload,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// unsafeFileChars matches characters not allowed in exported file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// rawPayloadFileName returns the file name of a raw payload export
+func rawPayloadFileName(msg *CapturedMessage) string {
+	subject := unsafeFileChars.ReplaceAllString(msg.Subject, "_")
+	return fmt.Sprintf("%06d_%s.bin", msg.Seq, subject)
+}
+
+// writeRawPayloads writes each message's payload to its own file in a folder
+func writeRawPayloads(folder fyne.ListableURI, msgs []*CapturedMessage) error {
+	for _, msg := range msgs {
+		uri, err := storage.Child(folder, rawPayloadFileName(msg))
+		if err != nil {
+			return err
+		}
+
+		writer, err := storage.Writer(uri)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(msg.Data)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %v", uri.Name(), err)
+		}
+	}
+	return nil
+}
+
+// ImportMessages adds previously exported messages to the message list
+func (nc *NATSClient) ImportMessages(msgs []*CapturedMessage) {
+	retention := nc.Retention()
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	for _, msg := range msgs {
+		nc.messageSeq++
+		msg.Seq = nc.messageSeq
+		nc.appendMessageLocked(msg, retention)
+	}
+}
+
+// Replay publishes messages again with their original subject, reply and headers
+func (nc *NATSClient) Replay(msgs []*CapturedMessage) error {
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("not connected to NATS server")
+	}
+
+	for _, msg := range msgs {
+		err := conn.PublishMsg(&nats.Msg{
+			Subject: msg.Subject,
+			Reply:   msg.Reply,
+			Header:  msg.Header,
+			Data:    msg.Data,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %v", msg.Subject, err)
+		}
+	}
+	return conn.Flush()
+}
+
+// showExportDialog asks for the export format and scope, then for the destination
+func showExportDialog(client *NATSClient, window fyne.Window, selected func() *CapturedMessage) {
+	formatSelect := widget.NewSelect([]string{ExportJSONL, ExportCSV, ExportRaw}, nil)
+	formatSelect.SetSelected(ExportJSONL)
+
+	scopeSelect := widget.NewSelect([]string{ExportAll, ExportFiltered, ExportSelected}, nil)
+	scopeSelect.SetSelected(ExportAll)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Format", formatSelect),
+		widget.NewFormItem("Messages", scopeSelect),
+	}
+
+	dialog.ShowForm("Export Messages", "Export", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var msgs []*CapturedMessage
+		switch scopeSelect.Selected {
+		case ExportFiltered:
+			msgs = client.GetFilteredMessages()
+		case ExportSelected:
+			if msg := selected(); msg != nil {
+				msgs = []*CapturedMessage{msg}
+			}
+		default:
+			msgs = client.GetMessages()
+		}
+
+		if len(msgs) == 0 {
+			dialog.ShowError(fmt.Errorf("no messages to export"), window)
+			return
+		}
+
+		done := func(err error) {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("export failed: %v", err), window)
+			} else {
+				dialog.ShowInformation("Export", fmt.Sprintf("Exported %d messages", len(msgs)), window)
+			}
+		}
+
+		if formatSelect.Selected == ExportRaw {
+			dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+				if err != nil || folder == nil {
+					return
+				}
+				done(writeRawPayloads(folder, msgs))
+			}, window)
+			return
+		}
+
+		fileName := "messages.jsonl"
+		if formatSelect.Selected == ExportCSV {
+			fileName = "messages.csv"
+		}
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if formatSelect.Selected == ExportCSV {
+				done(writeCSV(writer, msgs))
+			} else {
+				done(writeJSONLines(writer, msgs))
+			}
+		}, window)
+		saveDialog.SetFileName(fileName)
+		saveDialog.Show()
+	}, window)
+}
+
+// showImportDialog loads an exported JSON Lines file and offers to replay it
+func showImportDialog(client *NATSClient, window fyne.Window) {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		msgs, err := readJSONLines(reader)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("import failed: %v", err), window)
+			return
+		}
+		if len(msgs) == 0 {
+			dialog.ShowError(fmt.Errorf("no messages found in %s", reader.URI().Name()), window)
+			return
+		}
+
+		client.ImportMessages(msgs)
+
+		message := fmt.Sprintf("Imported %d messages from %s.\n\nReplay them now?", len(msgs), reader.URI().Name())
+		dialog.ShowConfirm("Import Messages", message, func(replay bool) {
+			if !replay {
+				return
+			}
+			if err := client.Replay(msgs); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			dialog.ShowInformation("Replay", fmt.Sprintf("Replayed %d messages", len(msgs)), window)
+		}, window)
+	}, window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".jsonl", ".json", ".ndjson"}))
+	openDialog.Show()
+}
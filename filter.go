@@ -0,0 +1,721 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// filterHelp describes the filter language in the filter help dialog
+const filterHelp = `Terms are combined with AND (the default between terms) and OR, negated with NOT
+and grouped with parentheses. AND binds tighter than OR.
+
+text                   formatted message contains text (case-insensitive)
+/regex/                formatted message matches the regular expression
+subject:orders.*       subject matches a NATS pattern (* = one token, > = the rest)
+payload:text           payload contains text (case-insensitive)
+payload:/regex/i       payload matches the regular expression, i = ignore case
+header:Key             header Key is present
+header:Key=value       header Key has the value
+header:Key=/regex/     a value of header Key matches the regular expression
+.order.status == "failed"   JSON field comparison: == != > < >= <= ~ contains
+.items[0].id           JSON field exists
+size > 1KB             payload size comparison
+
+Example: subject:orders.> AND (.order.status == "failed" OR header:Priority=high)`
+
+// messageMatcher reports whether a captured message passes a filter
+type messageMatcher func(msg *CapturedMessage) bool
+
+// FilterPreset is a saved filter expression
+type FilterPreset struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// filterToken is a lexical token of a filter expression
+type filterToken struct {
+	text string
+	// quoted tokens are never treated as keywords or operators
+	quoted bool
+}
+
+// comparisonOps are the operators of JSON field and size comparisons
+var comparisonOps = map[string]bool{
+	"==": true, "=": true, "!=": true, ">": true, "<": true, ">=": true, "<=": true, "~": true, "contains": true,
+}
+
+// compactComparison matches comparisons written without spaces, like size>1KB or .id==42
+var compactComparison = regexp.MustCompile(`(?i)^(size|\.[^\s=!<>~]*)(==|!=|>=|<=|=|>|<|~)(.*)$`)
+
+// tokenizeFilter splits a filter expression into tokens
+func tokenizeFilter(text string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+			continue
+		case r == '(' || r == ')':
+			tokens = append(tokens, filterToken{text: string(r)})
+			i++
+			continue
+		}
+
+		var b strings.Builder
+		quoted := false
+		for i < len(runes) {
+			r := runes[i]
+			if r == ' ' || r == '\t' || r == '\n' || r == '(' || r == ')' {
+				break
+			}
+
+			switch {
+			case r == '"':
+				// Quoted text, quotes are dropped; a token that starts quoted is plain text
+				if b.Len() == 0 {
+					quoted = true
+				}
+				end := i + 1
+				for end < len(runes) && runes[end] != '"' {
+					if runes[end] == '\\' && end+1 < len(runes) {
+						end++
+					}
+					b.WriteRune(runes[end])
+					end++
+				}
+				if end >= len(runes) {
+					return nil, fmt.Errorf("unterminated quote")
+				}
+				i = end + 1
+			case r == '/' && (b.Len() == 0 || strings.ContainsAny(b.String()[b.Len()-1:], ":=~")):
+				// Regular expression literal, kept verbatim with its slashes
+				end := i + 1
+				for end < len(runes) && runes[end] != '/' {
+					if runes[end] == '\\' && end+1 < len(runes) {
+						end++
+					}
+					end++
+				}
+				if end >= len(runes) {
+					return nil, fmt.Errorf("unterminated regular expression")
+				}
+				b.WriteString(string(runes[i : end+1]))
+				i = end + 1
+			default:
+				b.WriteRune(r)
+				i++
+			}
+		}
+		tokens = append(tokens, filterToken{text: b.String(), quoted: quoted})
+	}
+	return tokens, nil
+}
+
+// parseRegexLiteral compiles a /regex/ or /regex/i literal
+func parseRegexLiteral(text string) (*regexp.Regexp, bool, error) {
+	if len(text) < 2 || text[0] != '/' {
+		return nil, false, nil
+	}
+
+	flags := ""
+	end := strings.LastIndex(text, "/")
+	if end == 0 {
+		return nil, false, nil
+	}
+	switch text[end+1:] {
+	case "":
+	case "i":
+		flags = "(?i)"
+	default:
+		return nil, false, nil
+	}
+
+	re, err := regexp.Compile(flags + text[1:end])
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid regular expression %s: %v", text, err)
+	}
+	return re, true, nil
+}
+
+// filterParser is a recursive descent parser for filter expressions
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// parseFilter compiles a filter expression, an empty expression matches every message
+func parseFilter(text string) (messageMatcher, error) {
+	tokens, err := tokenizeFilter(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &filterParser{tokens: tokens}
+	matcher, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return matcher, nil
+}
+
+// peek returns the next token without consuming it
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// peekKeyword reports whether the next token is one of the given unquoted keywords
+func (p *filterParser) peekKeyword(keywords ...string) bool {
+	token, ok := p.peek()
+	if !ok || token.quoted {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(token.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOr parses terms separated by OR
+func (p *filterParser) parseOr() (messageMatcher, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekKeyword("OR", "||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(msg *CapturedMessage) bool { return a(msg) || b(msg) }
+	}
+	return left, nil
+}
+
+// parseAnd parses terms separated by AND or just by spaces
+func (p *filterParser) parseAnd() (messageMatcher, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.peekKeyword("AND", "&&") {
+			p.pos++
+		} else if _, ok := p.peek(); !ok || p.peekKeyword("OR", "||", ")") {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(msg *CapturedMessage) bool { return a(msg) && b(msg) }
+	}
+}
+
+// parseUnary parses a negation, a parenthesized expression or a single term
+func (p *filterParser) parseUnary() (messageMatcher, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+
+	switch {
+	case p.peekKeyword("NOT", "!"):
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(msg *CapturedMessage) bool { return !inner(msg) }, nil
+	case p.peekKeyword("("):
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekKeyword(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case p.peekKeyword(")", "AND", "&&", "OR", "||"):
+		return nil, fmt.Errorf("unexpected %q", token.text)
+	}
+
+	p.pos++
+	return p.parseTerm(token)
+}
+
+// parseTerm compiles a single filter term
+func (p *filterParser) parseTerm(token filterToken) (messageMatcher, error) {
+	text := token.text
+	if token.quoted {
+		return textMatcher(text), nil
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case strings.HasPrefix(lower, "subject:"):
+		pattern := text[len("subject:"):]
+		if pattern == "" {
+			return nil, fmt.Errorf("subject: needs a pattern")
+		}
+		return func(msg *CapturedMessage) bool { return subjectMatches(pattern, msg.Subject) }, nil
+
+	case strings.HasPrefix(lower, "payload:"):
+		value := text[len("payload:"):]
+		if re, ok, err := parseRegexLiteral(value); ok {
+			if err != nil {
+				return nil, err
+			}
+			return func(msg *CapturedMessage) bool { return re.Match(msg.Data) }, nil
+		}
+		value = strings.ToLower(value)
+		return func(msg *CapturedMessage) bool {
+			return strings.Contains(strings.ToLower(string(msg.Data)), value)
+		}, nil
+
+	case strings.HasPrefix(lower, "header:"):
+		return parseHeaderTerm(text[len("header:"):])
+
+	case lower == "size" || strings.HasPrefix(text, "."):
+		return p.parseComparison(text)
+	}
+
+	if compactComparison.MatchString(text) {
+		return p.parseComparison(text)
+	}
+
+	if re, ok, err := parseRegexLiteral(text); ok {
+		if err != nil {
+			return nil, err
+		}
+		return func(msg *CapturedMessage) bool { return re.MatchString(msg.Line()) }, nil
+	}
+
+	return textMatcher(text), nil
+}
+
+// textMatcher matches messages whose formatted line contains the text
+func textMatcher(text string) messageMatcher {
+	return func(msg *CapturedMessage) bool { return msg.matchesText(text) }
+}
+
+// parseHeaderTerm compiles a header:Key, header:Key=value or header:Key=/regex/ term
+func parseHeaderTerm(spec string) (messageMatcher, error) {
+	key, value, hasValue := strings.Cut(spec, "=")
+	if key == "" {
+		return nil, fmt.Errorf("header: needs a header name")
+	}
+
+	var match func(string) bool
+	if re, ok, err := parseRegexLiteral(value); ok {
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+	} else {
+		match = func(v string) bool { return v == value }
+	}
+
+	return func(msg *CapturedMessage) bool {
+		for name, values := range msg.Header {
+			if !strings.EqualFold(name, key) {
+				continue
+			}
+			if !hasValue {
+				return true
+			}
+			for _, v := range values {
+				if match(v) {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+// parseComparison compiles a size or JSON field comparison, reading the operator
+// and value from the following tokens unless they are part of the first one
+func (p *filterParser) parseComparison(text string) (messageMatcher, error) {
+	field, op, value := text, "", ""
+	hasValue := false
+
+	if m := compactComparison.FindStringSubmatch(text); m != nil {
+		field, op, value = m[1], m[2], m[3]
+		hasValue = value != ""
+	} else if next, ok := p.peek(); ok && !next.quoted && comparisonOps[strings.ToLower(next.text)] {
+		op = strings.ToLower(next.text)
+		p.pos++
+	}
+
+	if op != "" && !hasValue {
+		next, ok := p.peek()
+		if !ok {
+			return nil, fmt.Errorf("%s %s needs a value", field, op)
+		}
+		value = next.text
+		p.pos++
+	}
+	if op == "=" {
+		op = "=="
+	}
+
+	if strings.EqualFold(field, "size") {
+		return sizeMatcher(op, value)
+	}
+	return jsonFieldMatcher(field, op, value)
+}
+
+// sizeMatcher compiles a payload size comparison
+func sizeMatcher(op, value string) (messageMatcher, error) {
+	if op == "" || op == "~" || op == "contains" {
+		return nil, fmt.Errorf("size needs one of == != > < >= <=")
+	}
+	limit, err := parseByteSize(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(msg *CapturedMessage) bool {
+		return compareNumbers(float64(msg.Size()), op, float64(limit))
+	}, nil
+}
+
+// compareNumbers applies a comparison operator to two numbers
+func compareNumbers(a float64, op string, b float64) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case "<":
+		return a < b
+	case ">=":
+		return a >= b
+	case "<=":
+		return a <= b
+	}
+	return false
+}
+
+// jsonPathSegment is a key or array index of a JSON field path
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses paths like .order.status or .items[0].id
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	rest := path
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end > 0 {
+				segments = append(segments, jsonPathSegment{key: rest[:end]})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSON path %s: missing ]", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON path %s: bad index %q", path, rest[1:end])
+			}
+			segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSON path %s", path)
+		}
+	}
+	return segments, nil
+}
+
+// lookupJSONPath returns the value at a path of a decoded JSON document
+func lookupJSONPath(doc interface{}, segments []jsonPathSegment) (interface{}, bool) {
+	value := doc
+	for _, segment := range segments {
+		if segment.isIndex {
+			list, ok := value.([]interface{})
+			if !ok || segment.index < 0 || segment.index >= len(list) {
+				return nil, false
+			}
+			value = list[segment.index]
+			continue
+		}
+
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment.key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// jsonString formats a decoded JSON value for string comparisons
+func jsonString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// jsonFieldMatcher compiles a JSON field comparison, without operator it tests that the field exists
+func jsonFieldMatcher(path, op, value string) (messageMatcher, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var compare func(interface{}) bool
+	switch op {
+	case "":
+		compare = func(interface{}) bool { return true }
+	case "~":
+		re, ok, err := parseRegexLiteral(value)
+		if !ok {
+			re, err = regexp.Compile(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		compare = func(v interface{}) bool { return re.MatchString(jsonString(v)) }
+	case "contains":
+		compare = func(v interface{}) bool {
+			if list, ok := v.([]interface{}); ok {
+				for _, item := range list {
+					if jsonString(item) == value {
+						return true
+					}
+				}
+				return false
+			}
+			return strings.Contains(jsonString(v), value)
+		}
+	default:
+		number, numErr := strconv.ParseFloat(value, 64)
+		compare = func(v interface{}) bool {
+			if f, ok := v.(float64); ok && numErr == nil {
+				return compareNumbers(f, op, number)
+			}
+			s := jsonString(v)
+			switch op {
+			case "==":
+				return s == value
+			case "!=":
+				return s != value
+			case ">":
+				return s > value
+			case "<":
+				return s < value
+			case ">=":
+				return s >= value
+			case "<=":
+				return s <= value
+			}
+			return false
+		}
+	}
+
+	return func(msg *CapturedMessage) bool {
+		var doc interface{}
+		if err := json.Unmarshal(msg.Data, &doc); err != nil {
+			return false
+		}
+		v, ok := lookupJSONPath(doc, segments)
+		if !ok {
+			// A missing field is only "not equal"
+			return op == "!="
+		}
+		return compare(v)
+	}, nil
+}
+
+// subjectMatches reports whether a subject matches a NATS subject pattern
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		switch {
+		case token == ">":
+			// Matches one or more remaining tokens
+			return i == len(patternTokens)-1 && len(subjectTokens) > i
+		case i >= len(subjectTokens):
+			return false
+		case token != "*" && token != subjectTokens[i]:
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
+
+// GetFilterPresets returns the saved filter presets
+func (nc *NATSClient) GetFilterPresets() []FilterPreset {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return append([]FilterPreset{}, nc.config.FilterPresets...)
+}
+
+// GetFilterPresetNames returns the names of the saved filter presets
+func (nc *NATSClient) GetFilterPresetNames() []string {
+	presets := nc.GetFilterPresets()
+	names := make([]string, 0, len(presets))
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+	return names
+}
+
+// SaveFilterPreset adds or replaces a filter preset
+func (nc *NATSClient) SaveFilterPreset(preset FilterPreset) error {
+	if preset.Name == "" {
+		return fmt.Errorf("preset name cannot be empty")
+	}
+	if _, err := parseFilter(preset.Expression); err != nil {
+		return fmt.Errorf("invalid filter: %v", err)
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, existing := range nc.config.FilterPresets {
+		if existing.Name == preset.Name {
+			nc.config.FilterPresets[i] = preset
+			return saveConfig(nc.config)
+		}
+	}
+	nc.config.FilterPresets = append(nc.config.FilterPresets, preset)
+	return saveConfig(nc.config)
+}
+
+// DeleteFilterPreset removes the filter preset with the given name
+func (nc *NATSClient) DeleteFilterPreset(name string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, preset := range nc.config.FilterPresets {
+		if preset.Name == name {
+			nc.config.FilterPresets = append(nc.config.FilterPresets[:i], nc.config.FilterPresets[i+1:]...)
+			return saveConfig(nc.config)
+		}
+	}
+	return fmt.Errorf("preset %s not found", name)
+}
+
+// createFilterPresetControls creates the preset selector with save, delete and help buttons
+func createFilterPresetControls(client *NATSClient, window fyne.Window, filterEntry *widget.Entry) fyne.CanvasObject {
+	presetSelect := widget.NewSelect(client.GetFilterPresetNames(), func(selected string) {
+		for _, preset := range client.GetFilterPresets() {
+			if preset.Name == selected {
+				filterEntry.SetText(preset.Expression)
+				return
+			}
+		}
+	})
+	presetSelect.PlaceHolder = "(saved filters)"
+
+	refreshPresets := func(selected string) {
+		presetSelect.SetOptions(client.GetFilterPresetNames())
+		if selected == "" {
+			presetSelect.ClearSelected()
+		} else {
+			presetSelect.SetSelected(selected)
+		}
+	}
+
+	saveBtn := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		if strings.TrimSpace(filterEntry.Text) == "" {
+			dialog.ShowError(fmt.Errorf("enter a filter to save"), window)
+			return
+		}
+
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(presetSelect.Selected)
+		items := []*widget.FormItem{widget.NewFormItem("Name", nameEntry)}
+		dialog.ShowForm("Save Filter", "Save", "Cancel", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			name := strings.TrimSpace(nameEntry.Text)
+			if err := client.SaveFilterPreset(FilterPreset{Name: name, Expression: filterEntry.Text}); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refreshPresets(name)
+		}, window)
+	})
+
+	deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		name := presetSelect.Selected
+		if name == "" {
+			return
+		}
+		dialog.ShowConfirm("Delete Filter", fmt.Sprintf("Delete saved filter %s?", name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := client.DeleteFilterPreset(name); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refreshPresets("")
+		}, window)
+	})
+
+	helpBtn := widget.NewButtonWithIcon("", theme.QuestionIcon(), func() {
+		help := widget.NewLabel(filterHelp)
+		help.TextStyle = fyne.TextStyle{Monospace: true}
+		dialog.ShowCustom("Filter Syntax", "Close", help, window)
+	})
+
+	return container.NewBorder(nil, nil,
+		widget.NewLabel("Saved:"),
+		container.NewHBox(saveBtn, deleteBtn, helpBtn),
+		presetSelect,
+	)
+}
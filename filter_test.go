@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// testMessage builds a captured message for filter tests
+func testMessage(subject, payload string, header nats.Header) *CapturedMessage {
+	return &CapturedMessage{
+		Subject:    subject,
+		Header:     header,
+		Data:       []byte(payload),
+		ReceivedAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+}
+
+// matchFilter compiles a filter and applies it to a message
+func matchFilter(t *testing.T, filter string, msg *CapturedMessage) bool {
+	t.Helper()
+	matcher, err := parseFilter(filter)
+	if err != nil {
+		t.Fatalf("parseFilter(%q) failed: %v", filter, err)
+	}
+	if matcher == nil {
+		return true
+	}
+	return matcher(msg)
+}
+
+func TestParseFilterPrecedence(t *testing.T) {
+	order := testMessage("orders.created",
+		`{"order":{"status":"failed","total":42},"items":[{"id":"a1"}]}`,
+		nats.Header{"Priority": {"high"}})
+	login := testMessage("users.login", `{"user":"bob"}`, nil)
+
+	tests := []struct {
+		filter string
+		order  bool
+		login  bool
+	}{
+		{"", true, true},
+		{"subject:orders.*", true, false},
+		// AND binds tighter than OR
+		{"subject:users.* OR subject:orders.* header:Priority=low", false, true},
+		{"subject:users.* OR subject:orders.* AND header:Priority=high", true, true},
+		{"(subject:users.* OR subject:orders.*) header:Priority=high", true, false},
+		{"subject:orders.> && .order.total >= 40 || subject:users.login", true, true},
+		// NOT applies to the next term or group only
+		{"NOT subject:users.* payload:failed", true, false},
+		{"NOT (subject:users.* OR subject:orders.*)", false, false},
+		{"! subject:orders.* OR NOT subject:users.*", true, true},
+		{"not subject:orders.*", false, true},
+		{"((subject:orders.*))", true, false},
+		{".order.status == failed .items[0].id", true, false},
+		{".order.status != failed", false, true},
+		{"size>1KB", false, false},
+		{"size <= 1KB AND /user/", false, true},
+	}
+
+	for _, tt := range tests {
+		if got := matchFilter(t, tt.filter, order); got != tt.order {
+			t.Errorf("%q on %s: got %v, want %v", tt.filter, order.Subject, got, tt.order)
+		}
+		if got := matchFilter(t, tt.filter, login); got != tt.login {
+			t.Errorf("%q on %s: got %v, want %v", tt.filter, login.Subject, got, tt.login)
+		}
+	}
+}
+
+func TestParseFilterQuoting(t *testing.T) {
+	msg := testMessage("music.played",
+		`{"title":"rock and roll is not dead","quote":"say \"hi\"","path":"/tmp/x/"}`,
+		nats.Header{"Trace": {"abc-123"}})
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		// Quoted keywords are plain text
+		{`"and"`, true},
+		{`"OR"`, false},
+		{`"NOT" roll`, true},
+		{`"rock and roll"`, true},
+		{`"rock or roll"`, false},
+		{`payload:"and roll"`, true},
+		{`.title == "rock and roll is not dead"`, true},
+		{`.title == "rock"`, false},
+		{`.quote == "say \"hi\""`, true},
+		// Quoted slashes are not a regular expression
+		{`"/tmp/x/"`, true},
+		{`/r.ck/`, true},
+		{`payload:/ROCK/i`, true},
+		{`payload:/ROCK/`, false},
+		{`header:Trace=/^abc-\d+$/`, true},
+		{`header:trace=abc-123`, true},
+		{`header:Trace="abc 123"`, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchFilter(t, tt.filter, msg); got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		{`"unterminated`, "unterminated quote"},
+		{`payload:/abc`, "unterminated regular expression"},
+		{`(subject:a OR subject:b`, "missing closing parenthesis"},
+		{`subject:a)`, `unexpected ")"`},
+		{`()`, `unexpected ")"`},
+		{`subject:a OR`, "unexpected end of filter"},
+		{`NOT`, "unexpected end of filter"},
+		{`AND subject:a`, `unexpected "AND"`},
+		{`subject:a OR OR subject:b`, `unexpected "OR"`},
+		{`size >`, "size > needs a value"},
+		{`size ~ 1KB`, "size needs one of"},
+		{`payload:/[/`, "invalid regular expression"},
+		{`.items[x]`, "bad index"},
+		{`.items[0`, "missing ]"},
+		{`header:`, "needs a header name"},
+		{`subject:`, "needs a pattern"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseFilter(tt.filter)
+		if err == nil {
+			t.Errorf("%q: expected error containing %q", tt.filter, tt.err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q: got error %q, want %q", tt.filter, err, tt.err)
+		}
+	}
+}
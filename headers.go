@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// commonHeaders are offered as suggestions in the header editor
+var commonHeaders = []string{
+	nats.MsgIdHdr,
+	"Content-Type",
+	"Traceparent",
+	"X-Trace-Id",
+	"X-Request-Id",
+	nats.ExpectedStreamHdr,
+	nats.ExpectedLastSeqHdr,
+}
+
+// headerKeys returns the header keys in sorted order
+func headerKeys(header nats.Header) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatHeaders formats headers as one "Key: Value" line per value
+func formatHeaders(header nats.Header) string {
+	var lines []string
+	for _, key := range headerKeys(header) {
+		for _, value := range header[key] {
+			lines = append(lines, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatHeadersInline formats headers on a single line for message lists
+func formatHeadersInline(header nats.Header) string {
+	var pairs []string
+	for _, key := range headerKeys(header) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, strings.Join(header[key], ",")))
+	}
+	return "{" + strings.Join(pairs, "; ") + "}"
+}
+
+// headerRow is a single key/value row of the header editor
+type headerRow struct {
+	key   *widget.SelectEntry
+	value *widget.Entry
+	row   *fyne.Container
+}
+
+// headerEditor edits a list of message headers
+type headerEditor struct {
+	rows    []*headerRow
+	list    *fyne.Container
+	content *fyne.Container
+}
+
+// newHeaderEditor creates an empty header editor
+func newHeaderEditor() *headerEditor {
+	e := &headerEditor{list: container.NewVBox()}
+
+	addBtn := widget.NewButtonWithIcon("Add Header", theme.ContentAddIcon(), func() {
+		e.addRow("", "")
+	})
+
+	e.content = container.NewBorder(nil, addBtn, nil, nil, e.list)
+	return e
+}
+
+// addRow appends a header row with the given key and value
+func (e *headerEditor) addRow(key, value string) {
+	r := &headerRow{
+		key:   widget.NewSelectEntry(commonHeaders),
+		value: widget.NewEntry(),
+	}
+	r.key.SetPlaceHolder("Header")
+	r.key.SetText(key)
+	r.value.SetPlaceHolder("Value")
+	r.value.SetText(value)
+
+	removeBtn := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+		e.removeRow(r)
+	})
+
+	r.row = container.NewBorder(nil, nil, nil, removeBtn,
+		container.NewGridWithColumns(2, r.key, r.value))
+
+	e.rows = append(e.rows, r)
+	e.list.Add(r.row)
+}
+
+// removeRow removes a header row from the editor
+func (e *headerEditor) removeRow(r *headerRow) {
+	for i, existing := range e.rows {
+		if existing == r {
+			e.rows = append(e.rows[:i], e.rows[i+1:]...)
+			break
+		}
+	}
+	e.list.Remove(r.row)
+}
+
+// Header returns the edited headers, or nil when none are set
+func (e *headerEditor) Header() nats.Header {
+	var header nats.Header
+	for _, r := range e.rows {
+		key := strings.TrimSpace(r.key.Text)
+		if key == "" {
+			continue
+		}
+		if header == nil {
+			header = nats.Header{}
+		}
+		header.Add(key, r.value.Text)
+	}
+	return header
+}
+
+// SetHeader replaces the edited headers
+func (e *headerEditor) SetHeader(header nats.Header) {
+	e.Clear()
+	for _, key := range headerKeys(header) {
+		for _, value := range header[key] {
+			e.addRow(key, value)
+		}
+	}
+}
+
+// Clear removes all header rows
+func (e *headerEditor) Clear() {
+	e.rows = nil
+	e.list.RemoveAll()
+}
+
+// Widget returns the editor's canvas object
+func (e *headerEditor) Widget() fyne.CanvasObject {
+	return e.content
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Payload rendering formats of the message inspector
+const (
+	PayloadText   = "Text"
+	PayloadJSON   = "JSON"
+	PayloadHex    = "Hex"
+	PayloadBase64 = "Base64"
+)
+
+// renderPayload renders a payload in the given format
+func renderPayload(data []byte, format string) string {
+	switch format {
+	case PayloadJSON:
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return fmt.Sprintf("Not valid JSON: %v\n\n%s", err, strings.ToValidUTF8(string(data), "�"))
+		}
+		return out.String()
+	case PayloadHex:
+		return hex.Dump(data)
+	case PayloadBase64:
+		return base64.StdEncoding.EncodeToString(data)
+	default:
+		return strings.ToValidUTF8(string(data), "�")
+	}
+}
+
+// defaultPayloadFormat picks the most readable format for a payload
+func defaultPayloadFormat(data []byte) string {
+	if json.Valid(data) && len(bytes.TrimSpace(data)) > 0 {
+		return PayloadJSON
+	}
+	if !utf8.Valid(data) {
+		return PayloadHex
+	}
+	return PayloadText
+}
+
+// messageInspector shows the details of a single captured message
+type messageInspector struct {
+	message      *CapturedMessage
+	subject      *widget.Label
+	reply        *widget.Label
+	received     *widget.Label
+	size         *widget.Label
+	subscription *widget.Label
+	headers      *widget.Label
+	format       *widget.RadioGroup
+	payload      *widget.Entry
+	replyBtn     *widget.Button
+	content      fyne.CanvasObject
+}
+
+// newMessageInspector creates an empty message inspector, replies are sent through client
+func newMessageInspector(client *NATSClient, window fyne.Window) *messageInspector {
+	i := &messageInspector{
+		subject:      widget.NewLabel(""),
+		reply:        widget.NewLabel(""),
+		received:     widget.NewLabel(""),
+		size:         widget.NewLabel(""),
+		subscription: widget.NewLabel(""),
+		headers:      widget.NewLabel(""),
+		payload:      widget.NewMultiLineEntry(),
+	}
+	i.subject.Wrapping = fyne.TextWrapBreak
+	i.reply.Wrapping = fyne.TextWrapBreak
+	i.headers.Wrapping = fyne.TextWrapBreak
+	i.payload.Wrapping = fyne.TextWrapBreak
+	i.payload.SetPlaceHolder("Select a message to inspect it...")
+
+	i.format = widget.NewRadioGroup([]string{PayloadText, PayloadJSON, PayloadHex, PayloadBase64}, func(string) {
+		i.renderPayload()
+	})
+	i.format.Horizontal = true
+
+	copyBtn := widget.NewButton("Copy", func() {
+		window.Clipboard().SetContent(i.payload.Text)
+	})
+
+	// Only requests carrying a reply subject can be answered
+	i.replyBtn = widget.NewButtonWithIcon("Reply", theme.MailReplyIcon(), func() {
+		showReplyDialog(client, window, i.message)
+	})
+	i.replyBtn.Disable()
+
+	details := widget.NewForm(
+		widget.NewFormItem("Subject", i.subject),
+		widget.NewFormItem("Reply To", i.reply),
+		widget.NewFormItem("Received", i.received),
+		widget.NewFormItem("Size", i.size),
+		widget.NewFormItem("Subscription", i.subscription),
+		widget.NewFormItem("Headers", i.headers),
+	)
+
+	i.content = container.NewBorder(
+		container.NewVBox(
+			details,
+			container.NewBorder(nil, nil, nil, container.NewHBox(i.replyBtn, copyBtn), i.format),
+		),
+		nil, nil, nil,
+		container.NewScroll(i.payload),
+	)
+	return i
+}
+
+// Show displays a message in the inspector
+func (i *messageInspector) Show(msg *CapturedMessage) {
+	i.message = msg
+	if msg == nil {
+		i.Clear()
+		return
+	}
+
+	i.subject.SetText(msg.Subject)
+	if msg.Reply != "" {
+		i.reply.SetText(msg.Reply)
+		i.replyBtn.Enable()
+	} else {
+		i.reply.SetText("-")
+		i.replyBtn.Disable()
+	}
+	i.received.SetText(msg.ReceivedAt.Format("2006-01-02 15:04:05.000"))
+	i.size.SetText(fmt.Sprintf("%s (%d bytes)", formatBytes(uint64(msg.Size())), msg.Size()))
+	i.subscription.SetText(msg.Subscription)
+	if len(msg.Header) > 0 {
+		i.headers.SetText(formatHeaders(msg.Header))
+	} else {
+		i.headers.SetText("-")
+	}
+
+	// Selecting the format renders the payload
+	format := defaultPayloadFormat(msg.Data)
+	if i.format.Selected == format {
+		i.renderPayload()
+	} else {
+		i.format.SetSelected(format)
+	}
+}
+
+// Clear empties the inspector
+func (i *messageInspector) Clear() {
+	i.message = nil
+	for _, label := range []*widget.Label{i.subject, i.reply, i.received, i.size, i.subscription, i.headers} {
+		label.SetText("")
+	}
+	i.payload.SetText("")
+	i.replyBtn.Disable()
+}
+
+// renderPayload renders the current message's payload in the selected format
+func (i *messageInspector) renderPayload() {
+	if i.message == nil {
+		return
+	}
+	i.payload.SetText(renderPayload(i.message.Data, i.format.Selected))
+}
+
+// Widget returns the inspector's canvas object
+func (i *messageInspector) Widget() fyne.CanvasObject {
+	return i.content
+}
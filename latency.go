@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxLatencySamples is the number of request latencies kept per subject
+const maxLatencySamples = 100
+
+// maxLatencySubjects bounds the latency history when subjects carry unique IDs
+const maxLatencySubjects = 500
+
+// subjectLatency is the request latency history of a subject
+type subjectLatency struct {
+	// Samples are the latencies of answered requests, oldest first
+	Samples  []time.Duration
+	Requests int
+	Errors   int
+	LastAt   time.Time
+}
+
+// LatencySummary summarizes the request latencies of a subject
+type LatencySummary struct {
+	Subject  string
+	Requests int
+	Errors   int
+	Last     time.Duration
+	Min      time.Duration
+	Avg      time.Duration
+	P95      time.Duration
+	Max      time.Duration
+	LastAt   time.Time
+	// Recent are the latest latencies, newest first
+	Recent []time.Duration
+}
+
+// recordLatency adds a request round trip to the latency history of its subject
+func (nc *NATSClient) recordLatency(subject string, latency time.Duration, err error) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	if nc.latencies == nil {
+		nc.latencies = make(map[string]*subjectLatency)
+	}
+	history, ok := nc.latencies[subject]
+	if !ok {
+		// Forget the subject requested longest ago to make room
+		if len(nc.latencies) >= maxLatencySubjects {
+			oldest := ""
+			for s, h := range nc.latencies {
+				if oldest == "" || h.LastAt.Before(nc.latencies[oldest].LastAt) {
+					oldest = s
+				}
+			}
+			delete(nc.latencies, oldest)
+		}
+		history = &subjectLatency{}
+		nc.latencies[subject] = history
+	}
+
+	history.Requests++
+	history.LastAt = time.Now()
+	// Timeouts and missing responders have no round trip
+	if err != nil {
+		history.Errors++
+		return
+	}
+	history.Samples = append(history.Samples, latency)
+	if len(history.Samples) > maxLatencySamples {
+		history.Samples = history.Samples[len(history.Samples)-maxLatencySamples:]
+	}
+}
+
+// summarize computes the latency statistics of a subject
+func (h *subjectLatency) summarize(subject string) LatencySummary {
+	s := LatencySummary{
+		Subject:  subject,
+		Requests: h.Requests,
+		Errors:   h.Errors,
+		LastAt:   h.LastAt,
+	}
+	if len(h.Samples) == 0 {
+		return s
+	}
+
+	sorted := append([]time.Duration{}, h.Samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, sample := range sorted {
+		total += sample
+	}
+	s.Last = h.Samples[len(h.Samples)-1]
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+	s.Avg = total / time.Duration(len(sorted))
+	s.P95 = sorted[(len(sorted)*95+99)/100-1]
+
+	for i := len(h.Samples) - 1; i >= 0; i-- {
+		s.Recent = append(s.Recent, h.Samples[i])
+	}
+	return s
+}
+
+// LatencyHistory returns the latency summaries of all requested subjects, sorted by subject
+func (nc *NATSClient) LatencyHistory() []LatencySummary {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	summaries := make([]LatencySummary, 0, len(nc.latencies))
+	for subject, history := range nc.latencies {
+		summaries = append(summaries, history.summarize(subject))
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Subject < summaries[j].Subject })
+	return summaries
+}
+
+// ClearLatencyHistory forgets the recorded request latencies
+func (nc *NATSClient) ClearLatencyHistory() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.latencies = nil
+}
+
+// formatLatency formats a latency in milliseconds with microsecond precision
+func formatLatency(latency time.Duration) string {
+	return fmt.Sprintf("%.3f ms", float64(latency)/float64(time.Millisecond))
+}
+
+// formatLatencySummary formats the table columns of a latency summary
+func formatLatencySummary(s LatencySummary) []string {
+	columns := []string{s.Subject, fmt.Sprintf("%d", s.Requests), fmt.Sprintf("%d", s.Errors)}
+	if len(s.Recent) == 0 {
+		return append(columns, "-", "-", "-", "-", "-")
+	}
+	return append(columns,
+		formatLatency(s.Last),
+		formatLatency(s.Min),
+		formatLatency(s.Avg),
+		formatLatency(s.P95),
+		formatLatency(s.Max),
+	)
+}
+
+// showLatencyDialog shows the request latency history per subject
+func showLatencyDialog(client *NATSClient, window fyne.Window) {
+	headers := []string{"Subject", "Requests", "Errors", "Last", "Min", "Avg", "P95", "Max"}
+	rows := client.LatencyHistory()
+	selected := -1
+
+	recentLabel := widget.NewLabel("Select a subject to see its latest latencies")
+	recentLabel.Wrapping = fyne.TextWrapWord
+
+	table := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return len(rows), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row >= len(rows) {
+				label.SetText("")
+				return
+			}
+			label.SetText(formatLatencySummary(rows[id.Row])[id.Col])
+		},
+	)
+	table.ShowHeaderColumn = false
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		if id.Col >= 0 {
+			obj.(*widget.Label).SetText(headers[id.Col])
+		}
+	}
+	table.SetColumnWidth(0, 220)
+	for col := 1; col < len(headers); col++ {
+		table.SetColumnWidth(col, 90)
+	}
+
+	// Show the latest latencies of the selected subject
+	showRecent := func() {
+		if selected < 0 || selected >= len(rows) {
+			recentLabel.SetText("Select a subject to see its latest latencies")
+			return
+		}
+		row := rows[selected]
+		if len(row.Recent) == 0 {
+			recentLabel.SetText(fmt.Sprintf("%s: no answered requests", row.Subject))
+			return
+		}
+		recent := make([]string, 0, len(row.Recent))
+		for _, latency := range row.Recent {
+			recent = append(recent, formatLatency(latency))
+		}
+		recentLabel.SetText(fmt.Sprintf("%s, newest first: %s", row.Subject, strings.Join(recent, ", ")))
+	}
+
+	table.OnSelected = func(id widget.TableCellID) {
+		selected = id.Row
+		showRecent()
+	}
+
+	refreshBtn := widget.NewButton("Refresh", func() {
+		rows = client.LatencyHistory()
+		table.Refresh()
+		showRecent()
+	})
+	clearBtn := widget.NewButton("Clear", func() {
+		client.ClearLatencyHistory()
+		rows = nil
+		selected = -1
+		table.UnselectAll()
+		table.Refresh()
+		showRecent()
+	})
+
+	content := container.NewBorder(
+		nil,
+		container.NewVBox(recentLabel, container.NewHBox(refreshBtn, clearBtn)),
+		nil, nil,
+		table,
+	)
+
+	latencyDialog := dialog.NewCustom("Request Latency", "Close", content, window)
+	latencyDialog.Resize(fyne.NewSize(960, 480))
+	latencyDialog.Show()
+}
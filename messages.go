@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// messageRefreshInterval is how often captured messages are drawn into the message list
+const messageRefreshInterval = 100 * time.Millisecond
+
+// CapturedMessage is a message received by one of the client's subscriptions
+type CapturedMessage struct {
+	// Seq numbers messages in the order they were captured
+	Seq        uint64
+	Subject    string
+	Reply      string
+	Header     nats.Header
+	Data       []byte
+	ReceivedAt time.Time
+	// Subscription is the key of the subscription that received the message
+	Subscription string
+	Queue        string
+}
+
+// newCapturedMessage captures a received message
+func newCapturedMessage(msg *nats.Msg, subKey, queue string) *CapturedMessage {
+	return &CapturedMessage{
+		Subject:      msg.Subject,
+		Reply:        msg.Reply,
+		Header:       msg.Header,
+		Data:         msg.Data,
+		ReceivedAt:   time.Now(),
+		Subscription: subKey,
+		Queue:        queue,
+	}
+}
+
+// Size returns the payload size in bytes
+func (m *CapturedMessage) Size() int {
+	return len(m.Data)
+}
+
+// Line formats the message as a single line of the text view
+func (m *CapturedMessage) Line() string {
+	subject := m.Subject
+	if m.Queue != "" {
+		subject = fmt.Sprintf("%s@%s", m.Subject, m.Queue)
+	}
+
+	// Requests show where a reply would go
+	if m.Reply != "" {
+		subject = fmt.Sprintf("%s [reply: %s]", subject, m.Reply)
+	}
+
+	headers := ""
+	if len(m.Header) > 0 {
+		headers = formatHeadersInline(m.Header) + " "
+	}
+
+	// Keep multi-line payloads on one line of the message list
+	payload := strings.Join(strings.Fields(string(m.Data)), " ")
+
+	return fmt.Sprintf("[%s] %s: %s%s", m.ReceivedAt.Format("15:04:05"), subject, headers, payload)
+}
+
+// matchesText reports whether the formatted message contains the text, ignoring case
+func (m *CapturedMessage) matchesText(text string) bool {
+	return strings.Contains(strings.ToLower(m.Line()), strings.ToLower(text))
+}
+
+// messageHandler returns the handler capturing messages for a subscription
+func (nc *NATSClient) messageHandler(subKey, queue string) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		nc.addMessage(newCapturedMessage(msg, subKey, queue))
+	}
+}
+
+// GetMessages returns all captured messages
+func (nc *NATSClient) GetMessages() []*CapturedMessage {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return append([]*CapturedMessage{}, nc.allMessages...)
+}
+
+// GetFilteredMessages returns the captured messages matching the current filter
+func (nc *NATSClient) GetFilteredMessages() []*CapturedMessage {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return nc.filteredMessagesLocked()
+}
+
+// filteredMessagesLocked returns the messages shown in the combined view: matching the
+// filter and not muted (must be called with lock held)
+func (nc *NATSClient) filteredMessagesLocked() []*CapturedMessage {
+	var filtered []*CapturedMessage
+	for _, msg := range nc.allMessages {
+		if nc.matchesFilterLocked(msg) {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
+}
+
+// matchesFilterLocked reports whether a message is shown in the combined view (must be called with lock held)
+func (nc *NATSClient) matchesFilterLocked(msg *CapturedMessage) bool {
+	if nc.isMutedLocked(msg.Subscription) {
+		return false
+	}
+	return nc.filterMatch == nil || nc.filterMatch(msg)
+}
+
+// renderMessagesLocked recomputes the filtered messages after the filter changed (must be called with lock held)
+func (nc *NATSClient) renderMessagesLocked() {
+	nc.filteredMessages = nc.filteredMessagesLocked()
+	nc.messagesDirty = true
+}
+
+// FilteredMessageCount returns the number of messages shown in the list
+func (nc *NATSClient) FilteredMessageCount() int {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return len(nc.filteredMessages)
+}
+
+// flushMessageChanges updates the message counters if messages changed since
+// the last call, and reports whether the message list needs to be redrawn
+func (nc *NATSClient) flushMessageChanges() bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	// Messages buffered while paused only change the pending counter
+	if nc.pendingDirty {
+		nc.pendingDirty = false
+		nc.pendingCount.Set(len(nc.pausedMessages))
+		nc.pausedDroppedCount.Set(nc.pausedDropped)
+		nc.evictedCount.Set(nc.evicted)
+		nc.flushCaptureLocked()
+	}
+
+	if !nc.messagesDirty {
+		return false
+	}
+	nc.messagesDirty = false
+	nc.messageCount.Set(len(nc.filteredMessages))
+	nc.pendingCount.Set(len(nc.pausedMessages))
+	nc.pausedDroppedCount.Set(nc.pausedDropped)
+	nc.evictedCount.Set(nc.evicted)
+	nc.flushCaptureLocked()
+	return true
+}
+
+// FilteredMessageAt returns the message shown at the given list position
+func (nc *NATSClient) FilteredMessageAt(index int) *CapturedMessage {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	if index < 0 || index >= len(nc.filteredMessages) {
+		return nil
+	}
+	return nc.filteredMessages[index]
+}
+
+// SetPaused freezes or resumes the message display; paused messages are buffered
+func (nc *NATSClient) SetPaused(paused bool) {
+	retention := nc.Retention()
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	if nc.paused == paused {
+		return
+	}
+	nc.paused = paused
+	if paused {
+		return
+	}
+
+	// Flush the messages buffered while paused into the display
+	pending := nc.pausedMessages
+	nc.pausedMessages = nil
+	nc.pausedBytes = 0
+	nc.pausedDropped = 0
+	for _, msg := range pending {
+		nc.appendMessageLocked(msg, retention)
+	}
+	nc.messagesDirty = true
+}
+
+// IsPaused reports whether the message display is paused
+func (nc *NATSClient) IsPaused() bool {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return nc.paused
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// Default reconnect policy used when a profile does not override it
+const (
+	defaultMaxReconnects   = 5
+	defaultReconnectWait   = 2 * time.Second
+	defaultReconnectJitter = 100 * time.Millisecond
+	defaultConnectionName  = "Fyne NATS Client"
+)
+
+// ConnectionProfile holds a named set of connection settings
+type ConnectionProfile struct {
+	Name string `json:"name"`
+	// URL is a comma-separated list of servers
+	URL           string `json:"url"`
+	AuthMethod    string `json:"auth_method,omitempty"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Token         string `json:"token,omitempty"`
+	CredsFile     string `json:"creds_file,omitempty"`
+	NKeyFile      string `json:"nkey_file,omitempty"`
+	UserJWT       string `json:"user_jwt,omitempty"`
+	UserSeed      string `json:"user_seed,omitempty"`
+	CAFile        string `json:"ca_file,omitempty"`
+	CertFile      string `json:"cert_file,omitempty"`
+	KeyFile       string `json:"key_file,omitempty"`
+	TLSServerName string `json:"tls_server_name,omitempty"`
+	// TLSHandshakeFirst performs the TLS handshake before the server INFO
+	TLSHandshakeFirst bool   `json:"tls_handshake_first,omitempty"`
+	TLSInsecure       bool   `json:"tls_insecure,omitempty"`
+	ConnectionName    string `json:"connection_name,omitempty"`
+	// MaxReconnects of 0 uses the default, a negative value reconnects forever
+	MaxReconnects int    `json:"max_reconnects,omitempty"`
+	ReconnectWait string `json:"reconnect_wait,omitempty"`
+	// ReconnectJitter is the upper bound of a random delay added to ReconnectWait
+	ReconnectJitter string `json:"reconnect_jitter,omitempty"`
+	// RetryOnFailedConnect keeps retrying in the background when the first connect fails
+	RetryOnFailedConnect bool `json:"retry_on_failed_connect,omitempty"`
+	// Subscriptions are the subscriptions last active with this profile
+	Subscriptions []SavedSubscription `json:"subscriptions,omitempty"`
+	// RestoreSubscriptions restores the saved subscriptions on connect without asking
+	RestoreSubscriptions bool `json:"restore_subscriptions,omitempty"`
+}
+
+// Servers returns the profile's server URLs
+func (p *ConnectionProfile) Servers() []string {
+	var servers []string
+	for _, server := range strings.Split(p.URL, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// Auth returns the effective authentication method of the profile
+func (p *ConnectionProfile) Auth() string {
+	if p.AuthMethod != "" {
+		return p.AuthMethod
+	}
+	// Profiles written before auth methods existed only carry credentials
+	if p.Username != "" {
+		return AuthUserPassword
+	}
+	return AuthNone
+}
+
+// reconnectWait returns the parsed reconnect wait duration
+func (p *ConnectionProfile) reconnectWait() (time.Duration, error) {
+	if p.ReconnectWait == "" {
+		return defaultReconnectWait, nil
+	}
+	wait, err := time.ParseDuration(p.ReconnectWait)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reconnect wait: %v", err)
+	}
+	return wait, nil
+}
+
+// reconnectJitter returns the parsed reconnect jitter duration
+func (p *ConnectionProfile) reconnectJitter() (time.Duration, error) {
+	if p.ReconnectJitter == "" {
+		return defaultReconnectJitter, nil
+	}
+	jitter, err := time.ParseDuration(p.ReconnectJitter)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reconnect jitter: %v", err)
+	}
+	return jitter, nil
+}
+
+// Validate checks that the profile can be used to connect
+func (p *ConnectionProfile) Validate() error {
+	if len(p.Servers()) == 0 {
+		return fmt.Errorf("connection URL cannot be empty")
+	}
+	if _, err := p.reconnectWait(); err != nil {
+		return err
+	}
+	if _, err := p.reconnectJitter(); err != nil {
+		return err
+	}
+
+	if _, err := p.AuthSummary(); err != nil {
+		return fmt.Errorf("%s: %v", authMethodLabels[p.Auth()], err)
+	}
+
+	if p.usesTLS() {
+		if _, err := p.tlsConfig(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// natsOptions builds the nats.go options described by the profile
+func (p *ConnectionProfile) natsOptions() ([]nats.Option, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	name := p.ConnectionName
+	if name == "" {
+		name = defaultConnectionName
+	}
+
+	maxReconnects := p.MaxReconnects
+	if maxReconnects == 0 {
+		maxReconnects = defaultMaxReconnects
+	}
+
+	wait, _ := p.reconnectWait()
+
+	opts := []nats.Option{
+		nats.Name(name),
+		nats.ReconnectWait(wait),
+		nats.MaxReconnects(maxReconnects),
+	}
+
+	if p.RetryOnFailedConnect {
+		opts = append(opts, nats.RetryOnFailedConnect(true))
+	}
+
+	authOpts, err := p.authOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, authOpts...)
+
+	tlsOpts, err := p.tlsOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, tlsOpts...)
+
+	return opts, nil
+}
+
+// GetProfiles returns the configured connection profiles
+func (nc *NATSClient) GetProfiles() []ConnectionProfile {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return append([]ConnectionProfile{}, nc.config.Connections...)
+}
+
+// GetProfileNames returns the names of the configured connection profiles
+func (nc *NATSClient) GetProfileNames() []string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	names := make([]string, 0, len(nc.config.Connections))
+	for _, profile := range nc.config.Connections {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+// GetProfile returns the connection profile with the given name
+func (nc *NATSClient) GetProfile(name string) (ConnectionProfile, bool) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	for _, profile := range nc.config.Connections {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return ConnectionProfile{}, false
+}
+
+// GetLastProfile returns the name of the last used connection profile
+func (nc *NATSClient) GetLastProfile() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return nc.config.LastProfile
+}
+
+// SaveProfile adds or replaces a connection profile, renaming it from oldName if given
+func (nc *NATSClient) SaveProfile(oldName string, profile ConnectionProfile) error {
+	if profile.Name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	index := -1
+	for i, existing := range nc.config.Connections {
+		if existing.Name == profile.Name && existing.Name != oldName {
+			return fmt.Errorf("profile %s already exists", profile.Name)
+		}
+		if existing.Name == oldName && oldName != "" {
+			index = i
+		}
+	}
+
+	if index >= 0 {
+		nc.config.Connections[index] = profile
+	} else {
+		nc.config.Connections = append(nc.config.Connections, profile)
+	}
+
+	if oldName != "" && nc.config.LastProfile == oldName {
+		nc.config.LastProfile = profile.Name
+	}
+
+	return saveConfig(nc.config)
+}
+
+// DeleteProfile removes the connection profile with the given name
+func (nc *NATSClient) DeleteProfile(name string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, profile := range nc.config.Connections {
+		if profile.Name == name {
+			nc.config.Connections = append(nc.config.Connections[:i], nc.config.Connections[i+1:]...)
+			if nc.config.LastProfile == name {
+				nc.config.LastProfile = ""
+			}
+			return saveConfig(nc.config)
+		}
+	}
+	return fmt.Errorf("profile %s not found", name)
+}
+
+// SetLastProfile remembers the last used connection profile
+func (nc *NATSClient) SetLastProfile(name string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	nc.config.LastProfile = name
+
+	saveConfigAsync(nc.config)
+}
+
+// newFilePathEntry creates an entry for a file path with a browse button
+func newFilePathEntry(window fyne.Window, placeholder string) (*widget.Entry, fyne.CanvasObject) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeholder)
+
+	browseBtn := widget.NewButton("...", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			entry.SetText(reader.URI().Path())
+		}, window)
+	})
+
+	return entry, container.NewBorder(nil, nil, nil, browseBtn, entry)
+}
+
+// showProfileDialog opens the connection profile editor; an empty profile name creates a new one
+func showProfileDialog(client *NATSClient, window fyne.Window, profile ConnectionProfile, onSaved func(name string)) {
+	oldName := profile.Name
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(profile.Name)
+	nameEntry.SetPlaceHolder("Profile name")
+	nameEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("name cannot be empty")
+		}
+		return nil
+	}
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetText(profile.URL)
+	urlEntry.SetPlaceHolder("nats://host1:4222, nats://host2:4222")
+	urlEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("servers cannot be empty")
+		}
+		return nil
+	}
+
+	authEditor := newAuthEditor(window, profile)
+
+	tlsEditor := newTLSEditor(window, profile)
+
+	connNameEntry := widget.NewEntry()
+	connNameEntry.SetText(profile.ConnectionName)
+	connNameEntry.SetPlaceHolder(defaultConnectionName)
+
+	maxReconnectsEntry := widget.NewEntry()
+	if profile.MaxReconnects != 0 {
+		maxReconnectsEntry.SetText(strconv.Itoa(profile.MaxReconnects))
+	}
+	maxReconnectsEntry.SetPlaceHolder(fmt.Sprintf("%d (-1 for unlimited)", defaultMaxReconnects))
+	maxReconnectsEntry.Validator = func(text string) error {
+		if text == "" {
+			return nil
+		}
+		_, err := strconv.Atoi(text)
+		return err
+	}
+
+	reconnectWaitEntry := widget.NewEntry()
+	reconnectWaitEntry.SetText(profile.ReconnectWait)
+	reconnectWaitEntry.SetPlaceHolder(defaultReconnectWait.String())
+	reconnectWaitEntry.Validator = func(text string) error {
+		if text == "" {
+			return nil
+		}
+		_, err := time.ParseDuration(text)
+		return err
+	}
+
+	reconnectJitterEntry := widget.NewEntry()
+	reconnectJitterEntry.SetText(profile.ReconnectJitter)
+	reconnectJitterEntry.SetPlaceHolder(defaultReconnectJitter.String())
+	reconnectJitterEntry.Validator = reconnectWaitEntry.Validator
+
+	retryCheck := widget.NewCheck("Retry in background if the first connect fails", nil)
+	retryCheck.SetChecked(profile.RetryOnFailedConnect)
+
+	restoreCheck := widget.NewCheck("Restore saved subscriptions on connect without asking", nil)
+	restoreCheck.SetChecked(profile.RestoreSubscriptions)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Servers", urlEntry),
+	}
+	items = append(items, authEditor.formItems()...)
+	items = append(items, tlsEditor.formItems()...)
+	items = append(items,
+		widget.NewFormItem("Conn. Name", connNameEntry),
+		widget.NewFormItem("Max Reconnects", maxReconnectsEntry),
+		widget.NewFormItem("Reconnect Wait", reconnectWaitEntry),
+		widget.NewFormItem("Reconnect Jitter", reconnectJitterEntry),
+		widget.NewFormItem("", retryCheck),
+		widget.NewFormItem("Subscriptions", restoreCheck),
+	)
+
+	title := "Edit Connection Profile"
+	if oldName == "" {
+		title = "New Connection Profile"
+	}
+
+	formDialog := dialog.NewForm(title, "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		updated := profile
+		updated.Name = strings.TrimSpace(nameEntry.Text)
+		updated.URL = strings.TrimSpace(urlEntry.Text)
+		authEditor.apply(&updated)
+		tlsEditor.apply(&updated)
+		updated.ConnectionName = connNameEntry.Text
+		updated.MaxReconnects, _ = strconv.Atoi(maxReconnectsEntry.Text)
+		updated.ReconnectWait = reconnectWaitEntry.Text
+		updated.ReconnectJitter = reconnectJitterEntry.Text
+		updated.RetryOnFailedConnect = retryCheck.Checked
+		updated.RestoreSubscriptions = restoreCheck.Checked
+
+		// Subscriptions may have been saved while the dialog was open
+		if current, ok := client.GetProfile(oldName); ok {
+			updated.Subscriptions = current.Subscriptions
+		}
+
+		if err := updated.Validate(); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		if err := client.SaveProfile(oldName, updated); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		if onSaved != nil {
+			onSaved(updated.Name)
+		}
+	}, window)
+	formDialog.Resize(fyne.NewSize(560, 0))
+	formDialog.Show()
+}
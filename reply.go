@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// Reply answers a captured request by publishing to its reply subject
+func (nc *NATSClient) Reply(request *CapturedMessage, message string, header nats.Header) error {
+	if request.Reply == "" {
+		return fmt.Errorf("message on %s has no reply subject", request.Subject)
+	}
+
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("not connected to NATS server")
+	}
+	return conn.PublishMsg(&nats.Msg{
+		Subject: request.Reply,
+		Header:  header,
+		Data:    []byte(message),
+	})
+}
+
+// showReplyDialog opens an editor for answering a captured request
+func showReplyDialog(client *NATSClient, window fyne.Window, request *CapturedMessage) {
+	if request == nil || request.Reply == "" {
+		dialog.ShowError(fmt.Errorf("the selected message has no reply subject"), window)
+		return
+	}
+
+	replyLabel := widget.NewLabel(request.Reply)
+	replyLabel.Wrapping = fyne.TextWrapBreak
+
+	requestLabel := widget.NewLabel(fmt.Sprintf("%s at %s", request.Subject, request.ReceivedAt.Format("15:04:05.000")))
+	requestLabel.Wrapping = fyne.TextWrapBreak
+
+	headers := newHeaderEditor()
+	copyHeadersBtn := widget.NewButton("Copy Request Headers", func() {
+		headers.SetHeader(request.Header)
+	})
+	if len(request.Header) == 0 {
+		copyHeadersBtn.Disable()
+	}
+
+	payloadEntry := widget.NewMultiLineEntry()
+	payloadEntry.SetPlaceHolder("Reply payload")
+	payloadEntry.SetMinRowsVisible(6)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Reply To", replyLabel),
+		widget.NewFormItem("Request", requestLabel),
+		widget.NewFormItem("Headers", container.NewBorder(nil, copyHeadersBtn, nil, nil, headers.Widget())),
+		widget.NewFormItem("Payload", payloadEntry),
+	}
+
+	replyDialog := dialog.NewForm("Reply to Request", "Send", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if err := client.Reply(request, payloadEntry.Text, headers.Header()); err != nil {
+			dialog.ShowError(fmt.Errorf("reply failed: %v", err), window)
+			return
+		}
+		dialog.ShowInformation("Reply", fmt.Sprintf("Reply sent to %s", request.Reply), window)
+	}, window)
+	replyDialog.Resize(fyne.NewSize(600, 0))
+	replyDialog.Show()
+}
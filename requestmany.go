@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// RequestManyOptions bound how long a scatter-gather request collects responses
+type RequestManyOptions struct {
+	// Timeout is the total time to wait for responses
+	Timeout time.Duration
+	// MaxResponses stops collecting after this many responses, 0 for no limit
+	MaxResponses int
+	// IdleGap stops collecting when no response arrived for this long after the
+	// first one, 0 to wait for the timeout
+	IdleGap time.Duration
+}
+
+// parseRequestManyOptions parses the timeout, max responses and idle gap fields, where
+// empty fields mean a 5s timeout, no limit and no idle gap
+func parseRequestManyOptions(timeout, maxResponses, idleGap string) (RequestManyOptions, error) {
+	opts := RequestManyOptions{Timeout: 5 * time.Second}
+	var err error
+
+	if timeout = strings.TrimSpace(timeout); timeout != "" {
+		if opts.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return opts, fmt.Errorf("invalid timeout format: %v", err)
+		}
+		if opts.Timeout <= 0 {
+			return opts, fmt.Errorf("timeout must be positive, got %s", timeout)
+		}
+	}
+	if maxResponses = strings.TrimSpace(maxResponses); maxResponses != "" {
+		if opts.MaxResponses, err = strconv.Atoi(maxResponses); err != nil || opts.MaxResponses < 0 {
+			return opts, fmt.Errorf("invalid max responses %q", maxResponses)
+		}
+	}
+	if idleGap = strings.TrimSpace(idleGap); idleGap != "" {
+		if opts.IdleGap, err = time.ParseDuration(idleGap); err != nil {
+			return opts, fmt.Errorf("invalid idle gap format: %v", err)
+		}
+		if opts.IdleGap < 0 {
+			return opts, fmt.Errorf("idle gap cannot be negative, got %s", idleGap)
+		}
+	}
+	return opts, nil
+}
+
+// gatheredResponse is one of the responses to a scatter-gather request
+type gatheredResponse struct {
+	Msg     *nats.Msg
+	Latency time.Duration
+	// Results are the assertion results of this response
+	Results []AssertionResult
+}
+
+// RequestMany publishes a request with a reply inbox over the target's connection,
+// records every response until the timeout, the max count or the idle gap and checks
+// the assertions against each one. It reports whether the request passed: at least
+// one response arrived and every response passed its assertions
+func (nc *NATSClient) RequestMany(target *NATSClient, subject, message string, header nats.Header, opts RequestManyOptions, assertions []Assertion) (bool, error) {
+	target.mu.RLock()
+	conn := target.conn
+	target.mu.RUnlock()
+
+	if conn == nil {
+		return false, fmt.Errorf("not connected to NATS server")
+	}
+
+	// Note the connection used when it is not this one
+	requestLine := subject
+	if target != nc {
+		requestLine = fmt.Sprintf("%s (via %s)", subject, target.DisplayName())
+	}
+
+	// Failures before collecting are recorded like failed requests
+	start := time.Now()
+	fail := func(err error) (bool, error) {
+		nc.addResponse(fmt.Sprintf("[%s] REQUEST-MANY: %s\nERROR: %v\n%s",
+			start.Format("15:04:05.000"),
+			requestLine,
+			err,
+			strings.Repeat("-", 50)))
+		return false, err
+	}
+
+	// Subscribe to the inbox before publishing so no early response is missed
+	inbox := conn.NewRespInbox()
+	sub, err := conn.SubscribeSync(inbox)
+	if err != nil {
+		return fail(fmt.Errorf("failed to subscribe to reply inbox: %v", err))
+	}
+	defer sub.Unsubscribe()
+
+	err = conn.PublishMsg(&nats.Msg{
+		Subject: subject,
+		Reply:   inbox,
+		Header:  header,
+		Data:    []byte(message),
+	})
+	if err != nil {
+		return fail(fmt.Errorf("publish failed: %v", err))
+	}
+
+	var responses []gatheredResponse
+	stopReason := "timeout"
+	deadline := start.Add(opts.Timeout)
+	for {
+		if opts.MaxResponses > 0 && len(responses) >= opts.MaxResponses {
+			stopReason = "max responses"
+			break
+		}
+
+		wait := time.Until(deadline)
+		idle := false
+		if opts.IdleGap > 0 && len(responses) > 0 && opts.IdleGap < wait {
+			wait = opts.IdleGap
+			idle = true
+		}
+		if wait <= 0 {
+			break
+		}
+
+		msg, err := sub.NextMsg(wait)
+		if err == nats.ErrTimeout {
+			if idle {
+				stopReason = "idle gap"
+			}
+			break
+		}
+		if err != nil {
+			stopReason = err.Error()
+			break
+		}
+
+		// The server answers with a 503 status when nobody listens on the subject
+		if len(msg.Data) == 0 && msg.Header.Get("Status") == "503" {
+			if len(responses) == 0 {
+				err = nats.ErrNoResponders
+				nc.recordLatency(subject, time.Since(start), err)
+				nc.addResponse(fmt.Sprintf("[%s] REQUEST-MANY: %s\nERROR: %v\nHEADERS:\nStatus: 503\n%s",
+					start.Format("15:04:05.000"),
+					requestLine,
+					err,
+					strings.Repeat("-", 50)))
+				return false, err
+			}
+			continue
+		}
+
+		latency := time.Since(start)
+		responses = append(responses, gatheredResponse{
+			Msg:     msg,
+			Latency: latency,
+			Results: evaluateAssertions(assertions, msg, latency, nil),
+		})
+	}
+
+	// The history keeps the round trip of the fastest responder
+	if len(responses) > 0 {
+		nc.recordLatency(subject, responses[0].Latency, nil)
+	} else {
+		nc.recordLatency(subject, time.Since(start), nats.ErrTimeout)
+	}
+
+	nc.addResponse(formatGatheredResponses(requestLine, start, responses, stopReason))
+
+	passed := len(responses) > 0
+	for _, r := range responses {
+		passed = passed && requestPassed(r.Results, nil)
+	}
+	return passed, nil
+}
+
+// formatGatheredResponses formats the responses of a scatter-gather request, one block per responder
+func formatGatheredResponses(requestLine string, start time.Time, responses []gatheredResponse, stopReason string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] REQUEST-MANY: %s\n", start.Format("15:04:05.000"), requestLine)
+	fmt.Fprintf(&b, "RESPONSES: %d (stopped by %s after %s)\n",
+		len(responses), stopReason, formatLatency(time.Since(start)))
+
+	for i, r := range responses {
+		fmt.Fprintf(&b, "#%d  LATENCY: %s  SIZE: %s\n", i+1, formatLatency(r.Latency), formatBytes(uint64(len(r.Msg.Data))))
+		if len(r.Msg.Header) > 0 {
+			b.WriteString("HEADERS:\n" + formatHeaders(r.Msg.Header) + "\n")
+		}
+		b.WriteString(string(r.Msg.Data) + "\n")
+		if len(r.Results) > 0 {
+			b.WriteString(formatAssertionResults(r.Results) + "\n")
+		}
+		if i < len(responses)-1 {
+			b.WriteString(strings.Repeat(".", 20) + "\n")
+		}
+	}
+
+	b.WriteString(strings.Repeat("-", 50))
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRequestManyOptions(t *testing.T) {
+	tests := []struct {
+		timeout      string
+		maxResponses string
+		idleGap      string
+		want         RequestManyOptions
+	}{
+		// Empty fields mean a 5s timeout, no limit and no idle gap
+		{"", "", "", RequestManyOptions{Timeout: 5 * time.Second}},
+		{"  ", " ", " ", RequestManyOptions{Timeout: 5 * time.Second}},
+		{"2s", "3", "250ms", RequestManyOptions{Timeout: 2 * time.Second, MaxResponses: 3, IdleGap: 250 * time.Millisecond}},
+		{" 1m ", " 10 ", " 1s ", RequestManyOptions{Timeout: time.Minute, MaxResponses: 10, IdleGap: time.Second}},
+		// Zero max responses and idle gap disable the limits
+		{"1s", "0", "0s", RequestManyOptions{Timeout: time.Second}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRequestManyOptions(tt.timeout, tt.maxResponses, tt.idleGap)
+		if err != nil {
+			t.Errorf("(%q, %q, %q): unexpected error %v", tt.timeout, tt.maxResponses, tt.idleGap, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("(%q, %q, %q): got %+v, want %+v", tt.timeout, tt.maxResponses, tt.idleGap, got, tt.want)
+		}
+	}
+}
+
+func TestParseRequestManyOptionsErrors(t *testing.T) {
+	tests := []struct {
+		timeout      string
+		maxResponses string
+		idleGap      string
+		err          string
+	}{
+		{"soon", "", "", "invalid timeout format"},
+		{"0s", "", "", "timeout must be positive"},
+		{"-1s", "", "", "timeout must be positive"},
+		{"", "abc", "", "invalid max responses"},
+		{"", "-1", "", "invalid max responses"},
+		{"", "1.5", "", "invalid max responses"},
+		{"", "", "later", "invalid idle gap format"},
+		{"", "", "-1s", "idle gap cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseRequestManyOptions(tt.timeout, tt.maxResponses, tt.idleGap)
+		if err == nil {
+			t.Errorf("(%q, %q, %q): expected error containing %q", tt.timeout, tt.maxResponses, tt.idleGap, tt.err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("(%q, %q, %q): got error %q, want %q", tt.timeout, tt.maxResponses, tt.idleGap, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// rttInterval is how often the round trip time to the server is measured
+const rttInterval = 5 * time.Second
+
+// ServerDetails holds information about the connected server and the client connection
+type ServerDetails struct {
+	ServerID          string
+	ServerName        string
+	ServerVersion     string
+	ClusterName       string
+	ConnectedURL      string
+	ConnectedAddr     string
+	MaxPayload        int64
+	HeadersSupported  bool
+	AuthRequired      bool
+	TLSRequired       bool
+	Servers           []string
+	DiscoveredServers []string
+	JetStreamDomain   string
+	JetStreamError    error
+	Stats             nats.Statistics
+}
+
+// GetServerDetails collects information about the connected server
+func (nc *NATSClient) GetServerDetails() (*ServerDetails, error) {
+	nc.mu.RLock()
+	conn := nc.conn
+	js := nc.js
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return nil, fmt.Errorf("not connected to NATS server")
+	}
+	if !conn.IsConnected() {
+		return nil, fmt.Errorf("connection is %s", conn.Status())
+	}
+
+	details := &ServerDetails{
+		ServerID:          conn.ConnectedServerId(),
+		ServerName:        conn.ConnectedServerName(),
+		ServerVersion:     conn.ConnectedServerVersion(),
+		ClusterName:       conn.ConnectedClusterName(),
+		ConnectedURL:      conn.ConnectedUrlRedacted(),
+		ConnectedAddr:     conn.ConnectedAddr(),
+		MaxPayload:        conn.MaxPayload(),
+		HeadersSupported:  conn.HeadersSupported(),
+		AuthRequired:      conn.AuthRequired(),
+		TLSRequired:       conn.TLSRequired(),
+		Servers:           conn.Servers(),
+		DiscoveredServers: conn.DiscoveredServers(),
+		Stats:             conn.Stats(),
+	}
+
+	if js != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		info, err := js.AccountInfo(ctx)
+		if err != nil {
+			details.JetStreamError = err
+		} else {
+			details.JetStreamDomain = info.Domain
+		}
+	}
+
+	return details, nil
+}
+
+// MeasureRTT measures the round trip time to the connected server
+func (nc *NATSClient) MeasureRTT() (time.Duration, error) {
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return 0, fmt.Errorf("not connected to NATS server")
+	}
+	return conn.RTT()
+}
+
+// ConnectionStats returns the client's message and byte counters, without server traffic
+func (nc *NATSClient) ConnectionStats() (nats.Statistics, error) {
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return nats.Statistics{}, fmt.Errorf("not connected to NATS server")
+	}
+	return conn.Stats(), nil
+}
+
+// formatServerDetails formats server details for display
+func formatServerDetails(details *ServerDetails) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Server ID: %s\n", details.ServerID)
+	fmt.Fprintf(&b, "Server Name: %s\n", details.ServerName)
+	fmt.Fprintf(&b, "Version: %s\n", details.ServerVersion)
+	if details.ClusterName != "" {
+		fmt.Fprintf(&b, "Cluster: %s\n", details.ClusterName)
+	}
+	fmt.Fprintf(&b, "Connected URL: %s\n", details.ConnectedURL)
+	fmt.Fprintf(&b, "Address: %s\n", details.ConnectedAddr)
+	fmt.Fprintf(&b, "Max Payload: %s\n", formatBytes(uint64(details.MaxPayload)))
+	fmt.Fprintf(&b, "Headers Supported: %v\n", details.HeadersSupported)
+	fmt.Fprintf(&b, "Auth Required: %v\n", details.AuthRequired)
+	fmt.Fprintf(&b, "TLS Required: %v\n", details.TLSRequired)
+
+	switch {
+	case details.JetStreamError != nil:
+		fmt.Fprintf(&b, "JetStream: unavailable (%v)\n", details.JetStreamError)
+	case details.JetStreamDomain != "":
+		fmt.Fprintf(&b, "JetStream Domain: %s\n", details.JetStreamDomain)
+	default:
+		fmt.Fprintf(&b, "JetStream Domain: (default)\n")
+	}
+
+	fmt.Fprintf(&b, "\nServer Pool:\n")
+	for _, server := range details.Servers {
+		fmt.Fprintf(&b, "  %s\n", server)
+	}
+
+	if len(details.DiscoveredServers) > 0 {
+		fmt.Fprintf(&b, "\nDiscovered Cluster URLs:\n")
+		for _, server := range details.DiscoveredServers {
+			fmt.Fprintf(&b, "  %s\n", server)
+		}
+	}
+
+	return b.String()
+}
+
+// createServerInfoTab creates the server and connection details view
+func createServerInfoTab(client *NATSClient) *fyne.Container {
+	infoEntry := widget.NewMultiLineEntry()
+	infoEntry.SetPlaceHolder("Server information will appear here after connecting...")
+	infoEntry.Wrapping = fyne.TextWrapWord
+
+	rttLabel := widget.NewLabel("RTT: -")
+	inMsgsLabel := widget.NewLabel("In Msgs: 0")
+	outMsgsLabel := widget.NewLabel("Out Msgs: 0")
+	inBytesLabel := widget.NewLabel("In Bytes: 0 B")
+	outBytesLabel := widget.NewLabel("Out Bytes: 0 B")
+	reconnectsLabel := widget.NewLabel("Reconnects: 0")
+
+	// Update the counters and RTT, called periodically. Only the RTT ping goes to
+	// the server, so polling does not inflate the counters it shows
+	updateStats := func() {
+		rtt, rttErr := client.MeasureRTT()
+		stats, err := client.ConnectionStats()
+
+		fyne.Do(func() {
+			if rttErr != nil {
+				rttLabel.SetText("RTT: -")
+			} else {
+				rttLabel.SetText(fmt.Sprintf("RTT: %s", rtt.Round(time.Microsecond)))
+			}
+			if err != nil {
+				return
+			}
+			inMsgsLabel.SetText(fmt.Sprintf("In Msgs: %d", stats.InMsgs))
+			outMsgsLabel.SetText(fmt.Sprintf("Out Msgs: %d", stats.OutMsgs))
+			inBytesLabel.SetText(fmt.Sprintf("In Bytes: %s", formatBytes(stats.InBytes)))
+			outBytesLabel.SetText(fmt.Sprintf("Out Bytes: %s", formatBytes(stats.OutBytes)))
+			reconnectsLabel.SetText(fmt.Sprintf("Reconnects: %d", stats.Reconnects))
+		})
+	}
+
+	refreshInfo := func() {
+		go func() {
+			details, err := client.GetServerDetails()
+			fyne.Do(func() {
+				if err != nil {
+					infoEntry.SetText(fmt.Sprintf("Error: %v", err))
+					return
+				}
+				infoEntry.SetText(formatServerDetails(details))
+			})
+			updateStats()
+		}()
+	}
+
+	refreshBtn := widget.NewButton("Refresh", refreshInfo)
+
+	// Measure RTT periodically while the connection tab is open
+	go func() {
+		ticker := time.NewTicker(rttInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if !containsClient(client.manager.Clients(), client) {
+				return
+			}
+			updateStats()
+		}
+	}()
+
+	// Refresh details whenever the connection changes
+	client.manager.OnChange(client, refreshInfo)
+
+	statsSection := container.NewVBox(
+		widget.NewLabel("Client Statistics:"),
+		container.NewGridWithColumns(3,
+			rttLabel, inMsgsLabel, outMsgsLabel,
+			reconnectsLabel, inBytesLabel, outBytesLabel,
+		),
+	)
+
+	header := container.NewBorder(
+		nil, nil,
+		widget.NewLabel("Server Information:"),
+		refreshBtn,
+		nil,
+	)
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(statsSection, widget.NewSeparator(), header),
+		nil, nil, nil,
+		container.NewScroll(infoEntry),
+	))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Default retention of captured messages and request responses
+const (
+	defaultMaxMessages     = 1000
+	defaultMaxMessageBytes = 64 * 1024 * 1024
+	defaultMaxResponses    = 50
+)
+
+// RetentionSettings limits how much received data is kept in memory
+type RetentionSettings struct {
+	// MaxMessages is the number of captured messages kept per connection
+	MaxMessages int `json:"max_messages"`
+	// MaxMessageBytes limits the total payload size of the kept messages, 0 means no limit
+	MaxMessageBytes int64 `json:"max_message_bytes"`
+	// MaxResponses is the number of request responses kept per connection
+	MaxResponses int `json:"max_responses"`
+	// CaptureToDisk appends every captured message to a file in the config directory
+	CaptureToDisk bool `json:"capture_to_disk"`
+}
+
+// defaultRetention returns the default retention settings
+func defaultRetention() RetentionSettings {
+	return RetentionSettings{
+		MaxMessages:     defaultMaxMessages,
+		MaxMessageBytes: defaultMaxMessageBytes,
+		MaxResponses:    defaultMaxResponses,
+	}
+}
+
+// withDefaults fills unset limits with their defaults
+func (r RetentionSettings) withDefaults() RetentionSettings {
+	if r.MaxMessages <= 0 {
+		r.MaxMessages = defaultMaxMessages
+	}
+	if r.MaxMessageBytes < 0 {
+		r.MaxMessageBytes = 0
+	}
+	if r.MaxResponses <= 0 {
+		r.MaxResponses = defaultMaxResponses
+	}
+	return r
+}
+
+// Retention returns the retention settings shared by all connections
+func (nc *NATSClient) Retention() RetentionSettings {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return nc.config.Retention.withDefaults()
+}
+
+// SetRetention changes the retention settings and applies them to every connection
+func (m *ConnectionManager) SetRetention(retention RetentionSettings) {
+	configMu.Lock()
+	m.config.Retention = retention.withDefaults()
+	configMu.Unlock()
+	saveConfigAsync(m.config)
+
+	for _, client := range m.Clients() {
+		client.applyRetention()
+	}
+}
+
+// applyRetention trims the kept messages and responses to the current limits
+func (nc *NATSClient) applyRetention() {
+	retention := nc.Retention()
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	nc.trimMessagesLocked(retention)
+	nc.trimPausedLocked(retention)
+	nc.pendingDirty = true
+	nc.trimResponsesLocked(retention)
+	nc.renderResponsesLocked()
+	if !retention.CaptureToDisk {
+		nc.closeCaptureLocked()
+	}
+}
+
+// parseByteSize parses sizes like "512", "64KB", "16.5 MB" or "1GB", as printed by formatBytes
+func parseByteSize(text string) (int64, error) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	if text == "" {
+		return 0, nil
+	}
+
+	multiplier := float64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   float64
+	}{
+		{"TB", 1024 * 1024 * 1024 * 1024},
+		{"GB", 1024 * 1024 * 1024},
+		{"MB", 1024 * 1024},
+		{"KB", 1024},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(text, unit.suffix) {
+			multiplier = unit.size
+			text = strings.TrimSpace(strings.TrimSuffix(text, unit.suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+	return int64(value * multiplier), nil
+}
+
+// showSettingsDialog edits the application settings
+func showSettingsDialog(manager *ConnectionManager, window fyne.Window) {
+	configMu.RLock()
+	retention := manager.config.Retention.withDefaults()
+	configMu.RUnlock()
+
+	maxMessagesEntry := widget.NewEntry()
+	maxMessagesEntry.SetText(strconv.Itoa(retention.MaxMessages))
+
+	maxBytesEntry := widget.NewEntry()
+	maxBytesEntry.SetPlaceHolder("No limit")
+	if retention.MaxMessageBytes > 0 {
+		maxBytesEntry.SetText(formatBytes(uint64(retention.MaxMessageBytes)))
+	}
+
+	maxResponsesEntry := widget.NewEntry()
+	maxResponsesEntry.SetText(strconv.Itoa(retention.MaxResponses))
+
+	captureCheck := widget.NewCheck("Write every captured message to disk", nil)
+	captureCheck.SetChecked(retention.CaptureToDisk)
+
+	captureDir := "(unavailable)"
+	if dir, err := getCaptureDir(); err == nil {
+		captureDir = dir
+	}
+	captureHint := widget.NewLabel(fmt.Sprintf("Capture files are JSON Lines in %s and can be loaded with Import.", captureDir))
+	captureHint.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Max Messages", maxMessagesEntry),
+		widget.NewFormItem("Max Message Bytes", maxBytesEntry),
+		widget.NewFormItem("Max Responses", maxResponsesEntry),
+		widget.NewFormItem("Disk Capture", captureCheck),
+		widget.NewFormItem("", captureHint),
+	}
+
+	form := dialog.NewForm("Settings", "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		maxMessages, err := strconv.Atoi(strings.TrimSpace(maxMessagesEntry.Text))
+		if err != nil || maxMessages <= 0 {
+			dialog.ShowError(fmt.Errorf("max messages must be a positive number"), window)
+			return
+		}
+		maxBytes, err := parseByteSize(maxBytesEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("max message bytes: %v", err), window)
+			return
+		}
+		maxResponses, err := strconv.Atoi(strings.TrimSpace(maxResponsesEntry.Text))
+		if err != nil || maxResponses <= 0 {
+			dialog.ShowError(fmt.Errorf("max responses must be a positive number"), window)
+			return
+		}
+
+		manager.SetRetention(RetentionSettings{
+			MaxMessages:     maxMessages,
+			MaxMessageBytes: maxBytes,
+			MaxResponses:    maxResponses,
+			CaptureToDisk:   captureCheck.Checked,
+		})
+	}, window)
+	form.Resize(fyne.NewSize(500, 0))
+	form.Show()
+}
@@ -0,0 +1,504 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// statsHistory is the number of one-second buckets kept per subject and subscription
+const statsHistory = 60
+
+// maxStatsKeys bounds the number of subjects tracked, later subjects are counted as statsOther
+const maxStatsKeys = 1000
+
+// statsOther collects the traffic of subjects beyond maxStatsKeys
+const statsOther = "(other subjects)"
+
+// statsRefreshInterval is how often the statistics view is redrawn
+const statsRefreshInterval = time.Second
+
+// Statistics groupings
+const (
+	StatsBySubject      = "Subject"
+	StatsBySubscription = "Subscription"
+)
+
+// statsWindows are the sliding windows offered in the statistics view
+var statsWindows = map[string]int{
+	"5 seconds":  5,
+	"15 seconds": 15,
+	"60 seconds": 60,
+}
+
+// statsBucket counts the messages received during one second
+type statsBucket struct {
+	second  int64
+	msgs    int
+	bytes   int64
+	minSize int
+	maxSize int
+}
+
+// trafficStats keeps per-second message counts over the last statsHistory seconds
+type trafficStats struct {
+	buckets [statsHistory]statsBucket
+	total   int
+}
+
+// record counts a message received at the given time
+func (s *trafficStats) record(at time.Time, size int) {
+	second := at.Unix()
+	b := &s.buckets[second%statsHistory]
+	if b.second != second {
+		*b = statsBucket{second: second, minSize: size, maxSize: size}
+	}
+
+	b.msgs++
+	b.bytes += int64(size)
+	if size < b.minSize {
+		b.minSize = size
+	}
+	if size > b.maxSize {
+		b.maxSize = size
+	}
+	s.total++
+}
+
+// TrafficSummary describes the traffic of a subject or subscription over a window
+type TrafficSummary struct {
+	Name        string
+	MsgsPerSec  float64
+	BytesPerSec float64
+	// Payload sizes within the window
+	MinSize int
+	AvgSize float64
+	MaxSize int
+	// Total counts every message since the statistics were reset
+	Total int
+}
+
+// summary aggregates the last seconds of traffic, excluding the current incomplete second
+func (s *trafficStats) summary(name string, now time.Time, seconds int) TrafficSummary {
+	summary := TrafficSummary{Name: name, Total: s.total}
+
+	msgs := 0
+	var bytes int64
+	last := now.Unix() - 1
+	for second := last - int64(seconds) + 1; second <= last; second++ {
+		b := &s.buckets[second%statsHistory]
+		if b.second != second || b.msgs == 0 {
+			continue
+		}
+		if msgs == 0 || b.minSize < summary.MinSize {
+			summary.MinSize = b.minSize
+		}
+		if b.maxSize > summary.MaxSize {
+			summary.MaxSize = b.maxSize
+		}
+		msgs += b.msgs
+		bytes += b.bytes
+	}
+
+	summary.MsgsPerSec = float64(msgs) / float64(seconds)
+	summary.BytesPerSec = float64(bytes) / float64(seconds)
+	if msgs > 0 {
+		summary.AvgSize = float64(bytes) / float64(msgs)
+	}
+	return summary
+}
+
+// series returns the messages per second of the last statsHistory complete seconds, oldest first
+func (s *trafficStats) series(now time.Time) []float64 {
+	values := make([]float64, statsHistory)
+	last := now.Unix() - 1
+	for i := range values {
+		second := last - int64(statsHistory-1-i)
+		if b := &s.buckets[second%statsHistory]; b.second == second {
+			values[i] = float64(b.msgs)
+		}
+	}
+	return values
+}
+
+// trafficStatsSet tracks the traffic of a client by subject and by subscription
+type trafficStatsSet struct {
+	total          trafficStats
+	bySubject      map[string]*trafficStats
+	bySubscription map[string]*trafficStats
+	mu             sync.Mutex
+}
+
+// newTrafficStatsSet creates empty traffic statistics
+func newTrafficStatsSet() *trafficStatsSet {
+	return &trafficStatsSet{
+		bySubject:      make(map[string]*trafficStats),
+		bySubscription: make(map[string]*trafficStats),
+	}
+}
+
+// record counts a captured message
+func (t *trafficStatsSet) record(msg *CapturedMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total.record(msg.ReceivedAt, msg.Size())
+
+	subject := msg.Subject
+	if _, ok := t.bySubject[subject]; !ok && len(t.bySubject) >= maxStatsKeys {
+		subject = statsOther
+	}
+	statsFor(t.bySubject, subject).record(msg.ReceivedAt, msg.Size())
+	statsFor(t.bySubscription, msg.Subscription).record(msg.ReceivedAt, msg.Size())
+}
+
+// statsFor returns the statistics of a key, creating them on first use
+func statsFor(stats map[string]*trafficStats, key string) *trafficStats {
+	s, ok := stats[key]
+	if !ok {
+		s = &trafficStats{}
+		stats[key] = s
+	}
+	return s
+}
+
+// summaries returns the traffic of every subject or subscription over a window, busiest first
+func (t *trafficStatsSet) summaries(groupBy string, now time.Time, seconds int) []TrafficSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.bySubject
+	if groupBy == StatsBySubscription {
+		stats = t.bySubscription
+	}
+
+	summaries := make([]TrafficSummary, 0, len(stats))
+	for name, s := range stats {
+		summaries = append(summaries, s.summary(name, now, seconds))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].MsgsPerSec != summaries[j].MsgsPerSec {
+			return summaries[i].MsgsPerSec > summaries[j].MsgsPerSec
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+// series returns the per-second message counts of a subject or subscription, or of all
+// traffic when name is empty
+func (t *trafficStatsSet) series(groupBy, name string, now time.Time) []float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if name == "" {
+		return t.total.series(now)
+	}
+
+	stats := t.bySubject
+	if groupBy == StatsBySubscription {
+		stats = t.bySubscription
+	}
+	if s, ok := stats[name]; ok {
+		return s.series(now)
+	}
+	return make([]float64, statsHistory)
+}
+
+// totalSummary returns the traffic of all subjects over a window
+func (t *trafficStatsSet) totalSummary(now time.Time, seconds int) TrafficSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total.summary("All traffic", now, seconds)
+}
+
+// ResetStats clears the traffic statistics
+func (nc *NATSClient) ResetStats() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.stats = newTrafficStatsSet()
+}
+
+// trafficStatsSet returns the client's current traffic statistics
+func (nc *NATSClient) trafficStatsSet() *trafficStatsSet {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+	return nc.stats
+}
+
+// rateChart is a bar chart of the messages per second over the last minute
+type rateChart struct {
+	widget.BaseWidget
+	values []float64
+}
+
+// newRateChart creates an empty rate chart
+func newRateChart() *rateChart {
+	c := &rateChart{values: make([]float64, statsHistory)}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetValues replaces the charted values and redraws the chart
+func (c *rateChart) SetValues(values []float64) {
+	c.values = values
+	c.Refresh()
+}
+
+// CreateRenderer implements fyne.Widget
+func (c *rateChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &rateChartRenderer{
+		chart:      c,
+		background: canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground)),
+		maxText:    canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+	}
+	r.maxText.TextSize = theme.CaptionTextSize()
+	for i := 0; i < statsHistory; i++ {
+		r.bars = append(r.bars, canvas.NewRectangle(theme.Color(theme.ColorNamePrimary)))
+	}
+	r.Refresh()
+	return r
+}
+
+// rateChartRenderer draws a rateChart
+type rateChartRenderer struct {
+	chart      *rateChart
+	background *canvas.Rectangle
+	bars       []*canvas.Rectangle
+	maxText    *canvas.Text
+}
+
+// Layout sizes the bars relative to the largest value
+func (r *rateChartRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+	r.maxText.Move(fyne.NewPos(theme.Padding(), 0))
+
+	peak := 0.0
+	for _, v := range r.chart.values {
+		if v > peak {
+			peak = v
+		}
+	}
+
+	top := r.maxText.MinSize().Height
+	height := size.Height - top
+	barWidth := size.Width / float32(len(r.bars))
+	for i, bar := range r.bars {
+		value := 0.0
+		if i < len(r.chart.values) {
+			value = r.chart.values[i]
+		}
+		barHeight := float32(0)
+		if peak > 0 {
+			barHeight = height * float32(value/peak)
+		}
+		bar.Resize(fyne.NewSize(barWidth-1, barHeight))
+		bar.Move(fyne.NewPos(float32(i)*barWidth, size.Height-barHeight))
+	}
+}
+
+// MinSize implements fyne.WidgetRenderer
+func (r *rateChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(240, 100)
+}
+
+// Refresh updates the colors and the peak label, then lays out the bars again
+func (r *rateChartRenderer) Refresh() {
+	peak := 0.0
+	for _, v := range r.chart.values {
+		if v > peak {
+			peak = v
+		}
+	}
+	r.maxText.Text = fmt.Sprintf("peak %s msgs/s over %ds", formatCount(peak), statsHistory)
+	r.maxText.Color = theme.Color(theme.ColorNameForeground)
+	r.background.FillColor = theme.Color(theme.ColorNameInputBackground)
+	for _, bar := range r.bars {
+		bar.FillColor = theme.Color(theme.ColorNamePrimary)
+	}
+
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+// Objects implements fyne.WidgetRenderer
+func (r *rateChartRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.background}
+	for _, bar := range r.bars {
+		objects = append(objects, bar)
+	}
+	return append(objects, r.maxText)
+}
+
+// Destroy implements fyne.WidgetRenderer
+func (r *rateChartRenderer) Destroy() {}
+
+// formatCount formats a per-second count
+func formatCount(value float64) string {
+	if value < 10 && value != float64(int(value)) {
+		return fmt.Sprintf("%.1f", value)
+	}
+	return fmt.Sprintf("%.0f", value)
+}
+
+// formatSummary formats a traffic summary as the cells of a statistics table row
+func formatSummary(s TrafficSummary) []string {
+	return []string{
+		s.Name,
+		formatCount(s.MsgsPerSec),
+		formatBytes(uint64(s.BytesPerSec)) + "/s",
+		formatBytes(uint64(s.MinSize)),
+		formatBytes(uint64(s.AvgSize)),
+		formatBytes(uint64(s.MaxSize)),
+		fmt.Sprintf("%d", s.Total),
+	}
+}
+
+// createStatsTab creates the throughput statistics view
+func createStatsTab(client *NATSClient) *fyne.Container {
+	headers := []string{"Name", "Msgs/s", "Bytes/s", "Min Size", "Avg Size", "Max Size", "Total"}
+
+	// Rows of the table and the view settings, only touched on the UI goroutine
+	var rows []TrafficSummary
+	groupBy := StatsBySubject
+	window := 5
+	selected := ""
+
+	table := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return len(rows), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row >= len(rows) {
+				label.SetText("")
+				return
+			}
+			label.SetText(formatSummary(rows[id.Row])[id.Col])
+		},
+	)
+	table.ShowHeaderColumn = false
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		if id.Col >= 0 {
+			obj.(*widget.Label).SetText(headers[id.Col])
+		}
+	}
+	table.SetColumnWidth(0, 260)
+	for col := 1; col < len(headers); col++ {
+		table.SetColumnWidth(col, 90)
+	}
+
+	chart := newRateChart()
+	chartTitle := widget.NewLabel("All traffic")
+	totalLabel := widget.NewLabel("")
+
+	// refresh computes the statistics off the UI goroutine, from view settings copied on it
+	refresh := func(groupBy, selected string, window int) {
+		stats := client.trafficStatsSet()
+		now := time.Now()
+		summaries := stats.summaries(groupBy, now, window)
+		series := stats.series(groupBy, selected, now)
+		total := stats.totalSummary(now, window)
+
+		fyne.Do(func() {
+			rows = summaries
+			table.Refresh()
+			chart.SetValues(series)
+			totalLabel.SetText(fmt.Sprintf("All traffic: %s msgs/s, %s/s, %d messages",
+				formatCount(total.MsgsPerSec), formatBytes(uint64(total.BytesPerSec)), total.Total))
+		})
+	}
+
+	table.OnSelected = func(id widget.TableCellID) {
+		if id.Row < len(rows) {
+			selected = rows[id.Row].Name
+			chartTitle.SetText(fmt.Sprintf("%s: %s", groupBy, selected))
+			go refresh(groupBy, selected, window)
+		}
+	}
+
+	showAllBtn := widget.NewButton("All Traffic", func() {
+		selected = ""
+		chartTitle.SetText("All traffic")
+		table.UnselectAll()
+		go refresh(groupBy, selected, window)
+	})
+
+	groupSelect := widget.NewRadioGroup([]string{StatsBySubject, StatsBySubscription}, func(value string) {
+		groupBy = value
+		selected = ""
+		chartTitle.SetText("All traffic")
+		table.UnselectAll()
+		go refresh(groupBy, selected, window)
+	})
+	groupSelect.Horizontal = true
+	groupSelect.SetSelected(StatsBySubject)
+
+	windowNames := make([]string, 0, len(statsWindows))
+	for name := range statsWindows {
+		windowNames = append(windowNames, name)
+	}
+	sort.Slice(windowNames, func(i, j int) bool { return statsWindows[windowNames[i]] < statsWindows[windowNames[j]] })
+	windowSelect := widget.NewSelect(windowNames, func(value string) {
+		window = statsWindows[value]
+		go refresh(groupBy, selected, window)
+	})
+	windowSelect.SetSelected(windowNames[0])
+
+	resetBtn := widget.NewButton("Reset", func() {
+		client.ResetStats()
+		go refresh(groupBy, selected, window)
+	})
+
+	// Redraw while the connection tab is open
+	go func() {
+		ticker := time.NewTicker(statsRefreshInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if !containsClient(client.manager.Clients(), client) {
+				return
+			}
+			var g, sel string
+			var w int
+			fyne.DoAndWait(func() {
+				g, sel, w = groupBy, selected, window
+			})
+			refresh(g, sel, w)
+		}
+	}()
+
+	controls := container.NewHBox(
+		widget.NewLabel("Group by:"), groupSelect,
+		widget.NewLabel("Window:"), windowSelect,
+		resetBtn,
+	)
+
+	chartSection := container.NewBorder(
+		container.NewBorder(nil, nil, chartTitle, showAllBtn),
+		nil, nil, nil,
+		chart,
+	)
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(controls, totalLabel),
+		chartSection,
+		nil, nil,
+		table,
+	))
+}
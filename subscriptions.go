@@ -0,0 +1,581 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// subscriptionRefreshInterval is how often the subscription counters are redrawn
+const subscriptionRefreshInterval = time.Second
+
+// subscriptionColors tag messages in the combined view by the subscription that received them
+var subscriptionColors = []color.NRGBA{
+	{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+	{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+	{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+	{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+	{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+	{R: 0x8c, G: 0x56, B: 0x4b, A: 0xff},
+	{R: 0xe3, G: 0x77, B: 0xc2, A: 0xff},
+	{R: 0x17, G: 0xbe, B: 0xcf, A: 0xff},
+}
+
+// subscriptionState holds the display state of a subscription
+type subscriptionState struct {
+	Color color.NRGBA
+	// Count is the number of messages received by the subscription
+	Count int
+	// Muted subscriptions are hidden from the combined message view
+	Muted bool
+	// Pending limits of the subscription, 0 uses the nats.go default and -1 means no limit
+	PendingMsgsLimit  int
+	PendingBytesLimit int
+	// SlowConsumer is set when the subscription overflowed its pending limits
+	SlowConsumer bool
+}
+
+// pendingLimits returns the limits to apply with SetPendingLimits
+func (s *subscriptionState) pendingLimits() (int, int) {
+	msgs, bytes := s.PendingMsgsLimit, s.PendingBytesLimit
+	if msgs == 0 {
+		msgs = nats.DefaultSubPendingMsgsLimit
+	}
+	if bytes == 0 {
+		bytes = nats.DefaultSubPendingBytesLimit
+	}
+	return msgs, bytes
+}
+
+// SubscriptionStats reports the delivery statistics of an active subscription
+type SubscriptionStats struct {
+	PendingMsgs  int
+	PendingBytes int
+	MsgsLimit    int
+	BytesLimit   int
+	Dropped      int
+	Delivered    int64
+}
+
+// GetSubscriptionStats returns the delivery statistics of an active subscription
+func (nc *NATSClient) GetSubscriptionStats(subKey string) (SubscriptionStats, error) {
+	nc.mu.RLock()
+	sub, ok := nc.subscriptions[subKey]
+	nc.mu.RUnlock()
+
+	var stats SubscriptionStats
+	if !ok {
+		return stats, fmt.Errorf("not subscribed to %s", subKey)
+	}
+
+	var err error
+	if stats.PendingMsgs, stats.PendingBytes, err = sub.Pending(); err != nil {
+		return stats, err
+	}
+	if stats.MsgsLimit, stats.BytesLimit, err = sub.PendingLimits(); err != nil {
+		return stats, err
+	}
+	if stats.Dropped, err = sub.Dropped(); err != nil {
+		return stats, err
+	}
+	if stats.Delivered, err = sub.Delivered(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// SetPendingLimits changes the pending message and byte limits of a subscription,
+// 0 uses the nats.go default and -1 means no limit
+func (nc *NATSClient) SetPendingLimits(subKey string, msgs, bytes int) error {
+	nc.mu.Lock()
+	state := nc.subscriptionStateLocked(subKey)
+	state.PendingMsgsLimit, state.PendingBytesLimit = msgs, bytes
+	state.SlowConsumer = false
+
+	var err error
+	if sub, ok := nc.subscriptions[subKey]; ok {
+		err = sub.SetPendingLimits(state.pendingLimits())
+	}
+	nc.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to set pending limits: %v", err)
+	}
+	nc.persistSubscriptions("")
+	return nil
+}
+
+// markSlowConsumer flags the subscription that overflowed its pending limits
+func (nc *NATSClient) markSlowConsumer(sub *nats.Subscription) {
+	if sub == nil {
+		return
+	}
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	for subKey, s := range nc.subscriptions {
+		if s == sub {
+			nc.subscriptionStateLocked(subKey).SlowConsumer = true
+			return
+		}
+	}
+}
+
+// formatLimit formats a pending limit for display
+func formatLimit(limit int, format func(int) string) string {
+	if limit < 0 {
+		return "unlimited"
+	}
+	return format(limit)
+}
+
+// formatSubscriptionStats formats the counters shown under an active subscription
+func formatSubscriptionStats(state subscriptionState, stats SubscriptionStats) string {
+	formatCount := func(n int) string { return strconv.Itoa(n) }
+	formatSize := func(n int) string { return formatBytes(uint64(n)) }
+
+	text := fmt.Sprintf("%d received, %d delivered | pending %d/%s msgs, %s/%s | dropped %d",
+		state.Count, stats.Delivered,
+		stats.PendingMsgs, formatLimit(stats.MsgsLimit, formatCount),
+		formatBytes(uint64(stats.PendingBytes)), formatLimit(stats.BytesLimit, formatSize),
+		stats.Dropped)
+	if state.SlowConsumer {
+		text += " | SLOW CONSUMER"
+	}
+	return text
+}
+
+// showPendingLimitsDialog edits the pending limits of a subscription
+func showPendingLimitsDialog(client *NATSClient, window fyne.Window, subKey string) {
+	state := client.SubscriptionState(subKey)
+
+	msgsEntry := widget.NewEntry()
+	msgsEntry.SetPlaceHolder(fmt.Sprintf("Default (%d)", nats.DefaultSubPendingMsgsLimit))
+	if state.PendingMsgsLimit != 0 {
+		msgsEntry.SetText(strconv.Itoa(state.PendingMsgsLimit))
+	}
+
+	bytesEntry := widget.NewEntry()
+	bytesEntry.SetPlaceHolder(fmt.Sprintf("Default (%s)", formatBytes(nats.DefaultSubPendingBytesLimit)))
+	if state.PendingBytesLimit > 0 {
+		bytesEntry.SetText(formatBytes(uint64(state.PendingBytesLimit)))
+	} else if state.PendingBytesLimit < 0 {
+		bytesEntry.SetText("-1")
+	}
+
+	hint := widget.NewLabel("Messages beyond these limits are dropped and reported as a slow consumer.\nLeave empty for the default, -1 for no limit.")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Pending Messages", msgsEntry),
+		widget.NewFormItem("Pending Bytes", bytesEntry),
+		widget.NewFormItem("", hint),
+	}
+
+	dialog.ShowForm(fmt.Sprintf("Pending Limits - %s", subKey), "Apply", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		msgs, err := parsePendingLimit(msgsEntry.Text, func(text string) (int64, error) {
+			return strconv.ParseInt(text, 10, 64)
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("pending messages: %v", err), window)
+			return
+		}
+		bytes, err := parsePendingLimit(bytesEntry.Text, parseByteSize)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("pending bytes: %v", err), window)
+			return
+		}
+
+		if err := client.SetPendingLimits(subKey, msgs, bytes); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+}
+
+// parsePendingLimit parses a pending limit, empty is the default (0) and -1 means no limit
+func parsePendingLimit(text string, parse func(string) (int64, error)) (int, error) {
+	text = strings.TrimSpace(text)
+	switch text {
+	case "":
+		return 0, nil
+	case "-1":
+		return -1, nil
+	}
+
+	value, err := parse(text)
+	if err != nil {
+		return 0, err
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("limit must be positive, or -1 for no limit")
+	}
+	return int(value), nil
+}
+
+// subscriptionStateLocked returns the state of a subscription, creating it on first use (must be called with lock held)
+func (nc *NATSClient) subscriptionStateLocked(subKey string) *subscriptionState {
+	if nc.subscriptionStates == nil {
+		nc.subscriptionStates = make(map[string]*subscriptionState)
+	}
+
+	state, ok := nc.subscriptionStates[subKey]
+	if !ok {
+		state = &subscriptionState{Color: subscriptionColors[len(nc.subscriptionStates)%len(subscriptionColors)]}
+		nc.subscriptionStates[subKey] = state
+	}
+	return state
+}
+
+// SubscriptionState returns a copy of the display state of a subscription
+func (nc *NATSClient) SubscriptionState(subKey string) subscriptionState {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	if state, ok := nc.subscriptionStates[subKey]; ok {
+		return *state
+	}
+	return subscriptionState{}
+}
+
+// SubscriptionColor returns the color tag of a subscription, transparent for unknown subscriptions
+func (nc *NATSClient) SubscriptionColor(subKey string) color.Color {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	if state, ok := nc.subscriptionStates[subKey]; ok {
+		return state.Color
+	}
+	return color.Transparent
+}
+
+// SetSubscriptionMuted hides or shows a subscription's messages in the combined view
+func (nc *NATSClient) SetSubscriptionMuted(subKey string, muted bool) {
+	nc.mu.Lock()
+	nc.subscriptionStateLocked(subKey).Muted = muted
+	nc.renderMessagesLocked()
+	nc.mu.Unlock()
+
+	nc.persistSubscriptions("")
+}
+
+// isMutedLocked reports whether a subscription is muted (must be called with lock held)
+func (nc *NATSClient) isMutedLocked(subKey string) bool {
+	state, ok := nc.subscriptionStates[subKey]
+	return ok && state.Muted
+}
+
+// SubscriptionMessages returns the retained messages received by a subscription
+func (nc *NATSClient) SubscriptionMessages(subKey string) []*CapturedMessage {
+	nc.mu.RLock()
+	defer nc.mu.RUnlock()
+
+	var msgs []*CapturedMessage
+	for _, msg := range nc.allMessages {
+		if msg.Subscription == subKey {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// newColorSwatch creates the small color tag shown next to messages and subscriptions
+func newColorSwatch() *canvas.Rectangle {
+	swatch := canvas.NewRectangle(color.Transparent)
+	swatch.SetMinSize(fyne.NewSize(6, 0))
+	return swatch
+}
+
+// showSubscriptionWindow opens a window showing only the messages of one subscription
+func showSubscriptionWindow(client *NATSClient, subKey string) {
+	window := fyne.CurrentApp().NewWindow(fmt.Sprintf("%s - %s", subKey, client.DisplayName()))
+
+	inspector := newMessageInspector(client, window)
+
+	// Snapshot of the subscription's messages, only touched on the UI goroutine
+	var msgs []*CapturedMessage
+
+	messageList := widget.NewList(
+		func() int {
+			return len(msgs)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(msgs) {
+				obj.(*widget.Label).SetText(msgs[id].Line())
+			}
+		},
+	)
+	messageList.OnSelected = func(id widget.ListItemID) {
+		if id < len(msgs) {
+			inspector.Show(msgs[id])
+		}
+	}
+	messageList.OnUnselected = func(id widget.ListItemID) {
+		inspector.Clear()
+	}
+
+	countLabel := widget.NewLabel("Messages: 0")
+
+	autoScroll := true
+	autoScrollCheck := widget.NewCheck("Auto-scroll", func(checked bool) {
+		autoScroll = checked
+		if checked {
+			messageList.ScrollToBottom()
+		}
+	})
+	autoScrollCheck.SetChecked(true)
+
+	done := make(chan struct{})
+	window.SetOnClosed(func() {
+		close(done)
+	})
+
+	// Poll the shared message buffer, redrawing only when the subscription's messages changed
+	go func() {
+		ticker := time.NewTicker(messageRefreshInterval)
+		defer ticker.Stop()
+
+		var lastSeq uint64
+		lastLen := 0
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			if !containsClient(client.manager.Clients(), client) {
+				fyne.Do(window.Close)
+				return
+			}
+			// Pausing freezes this list like the combined one
+			if client.IsPaused() {
+				continue
+			}
+
+			latest := client.SubscriptionMessages(subKey)
+			var seq uint64
+			if len(latest) > 0 {
+				seq = latest[len(latest)-1].Seq
+			}
+			if seq == lastSeq && len(latest) == lastLen {
+				continue
+			}
+			lastSeq, lastLen = seq, len(latest)
+			received := client.SubscriptionState(subKey).Count
+
+			fyne.Do(func() {
+				msgs = latest
+				countLabel.SetText(fmt.Sprintf("Messages: %d (received %d)", len(msgs), received))
+				messageList.Refresh()
+				if autoScroll {
+					messageList.ScrollToBottom()
+				}
+			})
+		}
+	}()
+
+	messageSplit := container.NewVSplit(messageList, inspector.Widget())
+	messageSplit.SetOffset(0.5)
+
+	window.SetContent(container.NewBorder(
+		container.NewBorder(nil, nil, widget.NewLabel(subKey), container.NewHBox(countLabel, autoScrollCheck)),
+		nil, nil, nil,
+		messageSplit,
+	))
+	window.Resize(fyne.NewSize(800, 600))
+	window.Show()
+}
+
+// SavedSubscription is a subscription remembered in a connection profile
+type SavedSubscription struct {
+	Subject string `json:"subject"`
+	Queue   string `json:"queue,omitempty"`
+	Muted   bool   `json:"muted,omitempty"`
+	// Pending limits, 0 uses the nats.go default and -1 means no limit
+	PendingMsgs  int `json:"pending_msgs,omitempty"`
+	PendingBytes int `json:"pending_bytes,omitempty"`
+}
+
+// Key returns the subscription key used by the client
+func (s SavedSubscription) Key() string {
+	if s.Queue != "" {
+		return fmt.Sprintf("%s@%s", s.Subject, s.Queue)
+	}
+	return s.Subject
+}
+
+// persistSubscriptions saves the active subscriptions with the connected profile.
+// Saved subscriptions that are not active, e.g. because restoring them was skipped,
+// are kept unless removed names the subscription that was just unsubscribed
+func (nc *NATSClient) persistSubscriptions(removed string) {
+	nc.mu.RLock()
+	name := nc.profile.Name
+	active := make(map[string]SavedSubscription, len(nc.subscriptions))
+	for subKey, sub := range nc.subscriptions {
+		saved := SavedSubscription{Subject: sub.Subject, Queue: sub.Queue}
+		// Only read the state, creating it needs the write lock
+		if state, ok := nc.subscriptionStates[subKey]; ok {
+			saved.Muted = state.Muted
+			saved.PendingMsgs = state.PendingMsgsLimit
+			saved.PendingBytes = state.PendingBytesLimit
+		}
+		active[subKey] = saved
+	}
+	nc.mu.RUnlock()
+
+	// Ad hoc connections without a profile are not remembered
+	if name == "" {
+		return
+	}
+
+	configMu.Lock()
+	found := false
+	for i := range nc.config.Connections {
+		profile := &nc.config.Connections[i]
+		if profile.Name != name {
+			continue
+		}
+
+		saved := make([]SavedSubscription, 0, len(active)+len(profile.Subscriptions))
+		for _, s := range profile.Subscriptions {
+			if _, ok := active[s.Key()]; !ok && s.Key() != removed {
+				saved = append(saved, s)
+			}
+		}
+		for _, s := range active {
+			saved = append(saved, s)
+		}
+		sort.Slice(saved, func(i, j int) bool { return saved[i].Key() < saved[j].Key() })
+
+		profile.Subscriptions = saved
+		found = true
+		break
+	}
+	configMu.Unlock()
+
+	if found {
+		saveConfigAsync(nc.config)
+	}
+}
+
+// RestoreSubscriptions subscribes to saved subscriptions that are not active yet
+func (nc *NATSClient) RestoreSubscriptions(saved []SavedSubscription) error {
+	active := nc.GetSubscriptions()
+
+	var failed []string
+	for _, s := range saved {
+		if containsString(active, s.Key()) {
+			continue
+		}
+
+		// Limits are applied when subscribing
+		nc.mu.Lock()
+		state := nc.subscriptionStateLocked(s.Key())
+		state.PendingMsgsLimit, state.PendingBytesLimit = s.PendingMsgs, s.PendingBytes
+		nc.mu.Unlock()
+
+		if err := nc.subscribe(s.Subject, s.Queue); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", s.Key(), err))
+			continue
+		}
+
+		nc.mu.Lock()
+		nc.subscriptionStateLocked(s.Key()).Muted = s.Muted
+		nc.renderMessagesLocked()
+		nc.mu.Unlock()
+	}
+	nc.persistSubscriptions("")
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restore subscriptions:\n%s", strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// connectedProfile returns the saved profile the client connected with
+func (nc *NATSClient) connectedProfile() (ConnectionProfile, bool) {
+	nc.mu.RLock()
+	name := nc.profile.Name
+	nc.mu.RUnlock()
+
+	if name == "" {
+		return ConnectionProfile{}, false
+	}
+	return nc.GetProfile(name)
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// offerSubscriptionRestore restores the connected profile's saved subscriptions,
+// asking first unless the profile restores them automatically
+func offerSubscriptionRestore(client *NATSClient, window fyne.Window) {
+	profile, ok := client.connectedProfile()
+	if !ok || len(profile.Subscriptions) == 0 {
+		return
+	}
+
+	restore := func() {
+		if err := client.RestoreSubscriptions(profile.Subscriptions); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		client.recordEvent(EventRestored, "Restored %d saved subscriptions", len(profile.Subscriptions))
+	}
+
+	if profile.RestoreSubscriptions {
+		restore()
+		return
+	}
+
+	keys := make([]string, 0, len(profile.Subscriptions))
+	for _, s := range profile.Subscriptions {
+		keys = append(keys, s.Key())
+	}
+
+	alwaysCheck := widget.NewCheck("Always restore for this profile", nil)
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Restore the subscriptions saved with %s?", profile.Name)),
+		widget.NewLabel(strings.Join(keys, "\n")),
+		alwaysCheck,
+	)
+
+	dialog.ShowCustomConfirm("Restore Subscriptions", "Restore", "Skip", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		if alwaysCheck.Checked {
+			profile.RestoreSubscriptions = true
+			if err := client.SaveProfile(profile.Name, profile); err != nil {
+				dialog.ShowError(err, window)
+			}
+		}
+		restore()
+	}, window)
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// templateHelp describes the template variables in the variables dialog
+const templateHelp = `With "Expand {{placeholders}}" checked, placeholders are expanded in the subject,
+header values and payload when sending:
+
+{{uuid}}             random UUID
+{{now}}              current time, RFC 3339; {{now "15:04:05"}} uses a Go time layout
+{{unix}}             current Unix time in seconds
+{{seq}}              number of messages sent from this connection tab
+{{randInt 1 100}}    random integer between 1 and 100, inclusive
+{{.name}}            the user variable "name" defined below, or in the active environment`
+
+// variableNamePattern matches the variable names usable as {{.name}}
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// MessageTemplate is a saved message for the Publish tab
+type MessageTemplate struct {
+	Name    string      `json:"name"`
+	Subject string      `json:"subject"`
+	Headers nats.Header `json:"headers,omitempty"`
+	Body    string      `json:"body"`
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// randInt returns a random integer between min and max, inclusive
+func randInt(min, max int) (int, error) {
+	if max < min {
+		return 0, fmt.Errorf("randInt: max %d is less than min %d", max, min)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min)+1))
+	if err != nil {
+		return 0, err
+	}
+	return min + int(n.Int64()), nil
+}
+
+// expandTemplate expands the placeholders of a message field
+func expandTemplate(text string, vars map[string]string, seq uint64) (string, error) {
+	// Plain text is sent as is
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	funcs := template.FuncMap{
+		"uuid": newUUID,
+		"now": func(layout ...string) string {
+			if len(layout) > 0 {
+				return time.Now().Format(layout[0])
+			}
+			return time.Now().Format(time.RFC3339Nano)
+		},
+		"unix":    func() int64 { return time.Now().Unix() },
+		"seq":     func() uint64 { return seq },
+		"randInt": randInt,
+	}
+
+	tmpl, err := template.New("message").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// ExpandMessage expands the placeholders of a message's subject, headers and payload
+func (nc *NATSClient) ExpandMessage(subject, body string, header nats.Header) (string, string, nats.Header, error) {
+	vars := nc.ActiveVariables()
+
+	nc.mu.Lock()
+	nc.templateSeq++
+	seq := nc.templateSeq
+	nc.mu.Unlock()
+
+	expandedSubject, err := expandTemplate(subject, vars, seq)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("subject: %v", err)
+	}
+	expandedBody, err := expandTemplate(body, vars, seq)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("payload: %v", err)
+	}
+
+	var expandedHeader nats.Header
+	for key, values := range header {
+		if expandedHeader == nil {
+			expandedHeader = nats.Header{}
+		}
+		for _, value := range values {
+			expanded, err := expandTemplate(value, vars, seq)
+			if err != nil {
+				return "", "", nil, fmt.Errorf("header %s: %v", key, err)
+			}
+			expandedHeader.Add(key, expanded)
+		}
+	}
+
+	return expandedSubject, expandedBody, expandedHeader, nil
+}
+
+// GetMessageTemplates returns the saved message templates
+func (nc *NATSClient) GetMessageTemplates() []MessageTemplate {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return append([]MessageTemplate{}, nc.config.MessageTemplates...)
+}
+
+// GetMessageTemplateNames returns the names of the saved message templates
+func (nc *NATSClient) GetMessageTemplateNames() []string {
+	templates := nc.GetMessageTemplates()
+	names := make([]string, 0, len(templates))
+	for _, t := range templates {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// GetMessageTemplate returns the message template with the given name
+func (nc *NATSClient) GetMessageTemplate(name string) (MessageTemplate, bool) {
+	for _, t := range nc.GetMessageTemplates() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return MessageTemplate{}, false
+}
+
+// SaveMessageTemplate adds or replaces a message template
+func (nc *NATSClient) SaveMessageTemplate(t MessageTemplate) error {
+	if t.Name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, existing := range nc.config.MessageTemplates {
+		if existing.Name == t.Name {
+			nc.config.MessageTemplates[i] = t
+			return saveConfig(nc.config)
+		}
+	}
+	nc.config.MessageTemplates = append(nc.config.MessageTemplates, t)
+	return saveConfig(nc.config)
+}
+
+// DeleteMessageTemplate removes the message template with the given name
+func (nc *NATSClient) DeleteMessageTemplate(name string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for i, t := range nc.config.MessageTemplates {
+		if t.Name == name {
+			nc.config.MessageTemplates = append(nc.config.MessageTemplates[:i], nc.config.MessageTemplates[i+1:]...)
+			return saveConfig(nc.config)
+		}
+	}
+	return fmt.Errorf("template %s not found", name)
+}
+
+// GetTemplateVariables returns a copy of the user-defined template variables
+func (nc *NATSClient) GetTemplateVariables() map[string]string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	vars := make(map[string]string, len(nc.config.TemplateVariables))
+	for name, value := range nc.config.TemplateVariables {
+		vars[name] = value
+	}
+	return vars
+}
+
+// SetTemplateVariables replaces the user-defined template variables
+func (nc *NATSClient) SetTemplateVariables(vars map[string]string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	nc.config.TemplateVariables = vars
+	return saveConfig(nc.config)
+}
+
+// formatVariables formats variables as sorted name=value lines
+func formatVariables(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s=%s", name, vars[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseVariables parses name=value lines, ignoring blank lines and # comments
+func parseVariables(text string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected name=value", i+1)
+		}
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q, use letters, digits and underscores", i+1, name)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// showVariablesDialog edits the user-defined template variables
+func showVariablesDialog(client *NATSClient, window fyne.Window) {
+	varsEntry := widget.NewMultiLineEntry()
+	varsEntry.SetPlaceHolder("name=value, one per line")
+	varsEntry.SetText(formatVariables(client.GetTemplateVariables()))
+	varsEntry.SetMinRowsVisible(8)
+
+	help := widget.NewLabel(templateHelp)
+	help.TextStyle = fyne.TextStyle{Monospace: true}
+
+	content := container.NewBorder(help, nil, nil, nil, varsEntry)
+
+	varsDialog := dialog.NewCustomConfirm("Template Variables", "Save", "Cancel", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		vars, err := parseVariables(varsEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if err := client.SetTemplateVariables(vars); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+	varsDialog.Resize(fyne.NewSize(640, 480))
+	varsDialog.Show()
+}
+
+// createTemplateControls creates the template picker of the Publish tab. load fills the
+// publish controls from a template and current returns a template of their content
+func createTemplateControls(client *NATSClient, window fyne.Window, load func(MessageTemplate), current func() MessageTemplate) fyne.CanvasObject {
+	templateSelect := widget.NewSelect(client.GetMessageTemplateNames(), func(selected string) {
+		if t, ok := client.GetMessageTemplate(selected); ok {
+			load(t)
+		}
+	})
+	templateSelect.PlaceHolder = "(no template)"
+
+	refreshTemplates := func(selected string) {
+		templateSelect.SetOptions(client.GetMessageTemplateNames())
+		if selected == "" {
+			templateSelect.ClearSelected()
+		} else {
+			templateSelect.SetSelected(selected)
+		}
+	}
+
+	saveBtn := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(templateSelect.Selected)
+		items := []*widget.FormItem{widget.NewFormItem("Name", nameEntry)}
+		dialog.ShowForm("Save Template", "Save", "Cancel", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			t := current()
+			t.Name = strings.TrimSpace(nameEntry.Text)
+			if err := client.SaveMessageTemplate(t); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refreshTemplates(t.Name)
+		}, window)
+	})
+
+	deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		name := templateSelect.Selected
+		if name == "" {
+			return
+		}
+		dialog.ShowConfirm("Delete Template", fmt.Sprintf("Delete template %s?", name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := client.DeleteMessageTemplate(name); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refreshTemplates("")
+		}, window)
+	})
+
+	variablesBtn := widget.NewButton("Variables", func() {
+		showVariablesDialog(client, window)
+	})
+
+	return container.NewBorder(nil, nil,
+		widget.NewLabel("Template:"),
+		container.NewHBox(saveBtn, deleteBtn, variablesBtn),
+		templateSelect,
+	)
+}
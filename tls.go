@@ -0,0 +1,212 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/nats-io/nats.go"
+)
+
+// usesTLS reports whether the profile carries any TLS settings
+func (p *ConnectionProfile) usesTLS() bool {
+	return p.CAFile != "" || p.CertFile != "" || p.KeyFile != "" || p.TLSServerName != "" ||
+		p.TLSHandshakeFirst || p.TLSInsecure
+}
+
+// tlsConfig builds the TLS configuration described by the profile
+func (p *ConnectionProfile) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         p.TLSServerName,
+		InsecureSkipVerify: p.TLSInsecure,
+	}
+
+	if p.CAFile != "" {
+		pem, err := os.ReadFile(p.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", p.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (p.CertFile == "") != (p.KeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if p.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// tlsOptions builds the nats.go TLS options of the profile
+func (p *ConnectionProfile) tlsOptions() ([]nats.Option, error) {
+	if !p.usesTLS() {
+		return nil, nil
+	}
+
+	cfg, err := p.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []nats.Option{nats.Secure(cfg)}
+	if p.TLSHandshakeFirst {
+		opts = append(opts, nats.TLSHandshakeFirst())
+	}
+	return opts, nil
+}
+
+// TLSState returns the negotiated TLS state of the current connection
+func (nc *NATSClient) TLSState() (tls.ConnectionState, error) {
+	nc.mu.RLock()
+	conn := nc.conn
+	nc.mu.RUnlock()
+
+	if conn == nil {
+		return tls.ConnectionState{}, fmt.Errorf("not connected to NATS server")
+	}
+	return conn.TLSConnectionState()
+}
+
+// formatCertificateChain describes a negotiated TLS session and its peer certificates
+func formatCertificateChain(state tls.ConnectionState) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "TLS Version: %s\n", tls.VersionName(state.Version))
+	fmt.Fprintf(&b, "Cipher Suite: %s\n", tls.CipherSuiteName(state.CipherSuite))
+	if state.ServerName != "" {
+		fmt.Fprintf(&b, "Server Name: %s\n", state.ServerName)
+	}
+	fmt.Fprintf(&b, "Verified Chains: %d\n", len(state.VerifiedChains))
+
+	for i, cert := range state.PeerCertificates {
+		fingerprint := sha256.Sum256(cert.Raw)
+
+		fmt.Fprintf(&b, "\n[%d] %s\n", i, cert.Subject.String())
+		fmt.Fprintf(&b, "  Issuer: %s\n", cert.Issuer.String())
+		fmt.Fprintf(&b, "  Serial: %s\n", cert.SerialNumber.String())
+		fmt.Fprintf(&b, "  Valid: %s - %s\n",
+			cert.NotBefore.Format("2006-01-02 15:04:05"),
+			cert.NotAfter.Format("2006-01-02 15:04:05"))
+		if len(cert.DNSNames) > 0 {
+			fmt.Fprintf(&b, "  DNS Names: %s\n", strings.Join(cert.DNSNames, ", "))
+		}
+		if len(cert.IPAddresses) > 0 {
+			ips := make([]string, 0, len(cert.IPAddresses))
+			for _, ip := range cert.IPAddresses {
+				ips = append(ips, ip.String())
+			}
+			fmt.Fprintf(&b, "  IP Addresses: %s\n", strings.Join(ips, ", "))
+		}
+		fmt.Fprintf(&b, "  CA: %v\n", cert.IsCA)
+		fmt.Fprintf(&b, "  SHA-256: %X\n", fingerprint)
+	}
+
+	return b.String()
+}
+
+// showCertificatesDialog shows the server certificate chain of the current connection
+func showCertificatesDialog(client *NATSClient, window fyne.Window) {
+	state, err := client.TLSState()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("no TLS session: %v", err), window)
+		return
+	}
+
+	text := widget.NewMultiLineEntry()
+	text.SetText(formatCertificateChain(state))
+	text.Wrapping = fyne.TextWrapBreak
+
+	scroll := container.NewScroll(text)
+	scroll.SetMinSize(fyne.NewSize(620, 420))
+
+	dialog.ShowCustom("Server Certificates", "Close", scroll, window)
+}
+
+// tlsEditor holds the widgets used to edit a profile's TLS settings
+type tlsEditor struct {
+	caFile        *widget.Entry
+	certFile      *widget.Entry
+	keyFile       *widget.Entry
+	serverName    *widget.Entry
+	handshake     *widget.Check
+	insecure      *widget.Check
+	caRow         fyne.CanvasObject
+	certRow       fyne.CanvasObject
+	keyRow        fyne.CanvasObject
+	insecureLabel *widget.Label
+}
+
+// newTLSEditor creates the TLS editor for a profile
+func newTLSEditor(window fyne.Window, profile ConnectionProfile) *tlsEditor {
+	e := &tlsEditor{
+		serverName:    widget.NewEntry(),
+		handshake:     widget.NewCheck("TLS handshake first", nil),
+		insecureLabel: widget.NewLabel(""),
+	}
+
+	e.caFile, e.caRow = newFilePathEntry(window, "CA bundle (PEM)")
+	e.caFile.SetText(profile.CAFile)
+	e.certFile, e.certRow = newFilePathEntry(window, "Client certificate (PEM)")
+	e.certFile.SetText(profile.CertFile)
+	e.keyFile, e.keyRow = newFilePathEntry(window, "Client key (PEM)")
+	e.keyFile.SetText(profile.KeyFile)
+
+	e.serverName.SetPlaceHolder("Override server name (SNI)")
+	e.serverName.SetText(profile.TLSServerName)
+	e.handshake.SetChecked(profile.TLSHandshakeFirst)
+
+	e.insecureLabel.Importance = widget.DangerImportance
+	e.insecure = widget.NewCheck("Skip certificate verification (insecure)", func(checked bool) {
+		if checked {
+			e.insecureLabel.SetText("Warning: server identity will NOT be verified")
+			e.insecureLabel.Show()
+		} else {
+			e.insecureLabel.Hide()
+		}
+	})
+	e.insecure.SetChecked(profile.TLSInsecure)
+	if !profile.TLSInsecure {
+		e.insecureLabel.Hide()
+	}
+
+	return e
+}
+
+// apply copies the edited TLS settings into the profile
+func (e *tlsEditor) apply(profile *ConnectionProfile) {
+	profile.CAFile = e.caFile.Text
+	profile.CertFile = e.certFile.Text
+	profile.KeyFile = e.keyFile.Text
+	profile.TLSServerName = strings.TrimSpace(e.serverName.Text)
+	profile.TLSHandshakeFirst = e.handshake.Checked
+	profile.TLSInsecure = e.insecure.Checked
+}
+
+// formItems returns the form rows of the TLS editor
+func (e *tlsEditor) formItems() []*widget.FormItem {
+	return []*widget.FormItem{
+		widget.NewFormItem("CA File", e.caRow),
+		widget.NewFormItem("Client Cert", e.certRow),
+		widget.NewFormItem("Client Key", e.keyRow),
+		widget.NewFormItem("Server Name", e.serverName),
+		widget.NewFormItem("TLS", container.NewVBox(e.handshake, e.insecure, e.insecureLabel)),
+	}
+}